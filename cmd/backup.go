@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/5ouma/dorg/internal/backup"
+	"github.com/5ouma/dorg/internal/command"
+	"github.com/5ouma/dorg/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func newBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Manage Dock backups",
+		Long:  "🗄️ Manage snapshots of the Dock preferences taken before each load",
+		Args:  cobra.NoArgs,
+	}
+	addTargetFlags(cmd, false)
+	addBackupFlags(cmd)
+	cmd.PersistentFlags().BoolP("verbose", "V", false, "verbose output")
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "List Dock backups",
+			Long:  "🗄️ List the stored Dock backups, newest first",
+			Args:  cobra.NoArgs,
+			RunE:  execBackupListCmd,
+		},
+		&cobra.Command{
+			Use:   "create",
+			Short: "Create a Dock backup",
+			Long:  "🗄️ Take a snapshot of the current Dock preferences",
+			Args:  cobra.NoArgs,
+			RunE:  execBackupCreateCmd,
+		},
+	)
+	return cmd
+}
+
+func addBackupFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("backup-dir", "", "backup directory (default $DORG_BACKUP_DIR or $XDG_STATE_HOME/dorg/backups)")
+	cmd.PersistentFlags().Int("keep", backup.DefaultKeep, "number of backups to keep, 0 for all (or $DORG_BACKUP_KEEP)")
+}
+
+func backupStore(cmd *cobra.Command) (*backup.Store, error) {
+	dir, err := cmd.Flags().GetString("backup-dir")
+	if err != nil {
+		return nil, err
+	}
+	keep, err := cmd.Flags().GetInt("keep")
+	if err != nil {
+		return nil, err
+	}
+
+	if dir == "" {
+		if dir, err = backup.DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+	if !cmd.Flags().Changed("keep") {
+		if keep, err = backup.DefaultKeepCount(); err != nil {
+			return nil, err
+		}
+	}
+	return &backup.Store{Dir: dir, Keep: keep}, nil
+}
+
+func setVerbose(cmd *cobra.Command) error {
+	verbose, err := cmd.Flags().GetBool("verbose")
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	}
+	return nil
+}
+
+func execBackupListCmd(cmd *cobra.Command, args []string) error {
+	if err := setVerbose(cmd); err != nil {
+		return err
+	}
+	store, err := backupStore(cmd)
+	if err != nil {
+		return err
+	}
+
+	backups, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(utils.H1.Render("🗄️ Dock Backups"))
+	if len(backups) == 0 {
+		fmt.Println(utils.Msg.Render("No backups in", store.Dir))
+		return nil
+	}
+	for _, b := range backups {
+		fmt.Println(utils.CheckedItem.Render(), b.ID, b.Time.Local().Format("2006-01-02 15:04:05"), fmt.Sprintf("(%d bytes)", b.Size))
+	}
+	return nil
+}
+
+func execBackupCreateCmd(cmd *cobra.Command, args []string) error {
+	if err := setVerbose(cmd); err != nil {
+		return err
+	}
+	store, err := backupStore(cmd)
+	if err != nil {
+		return err
+	}
+	target, err := targetFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(utils.H1.Render("🗄️ Back up Dock settings"))
+	b, err := command.CreateBackup(&command.Config{Cmd: cmd.Use, Backup: store, Target: target})
+	if err != nil {
+		return err
+	}
+	fmt.Println(utils.Msg.Render("✅", b.Path))
+	return nil
+}
@@ -1,14 +1,13 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
 
+	"github.com/5ouma/dorg/internal/command"
 	"github.com/5ouma/dorg/internal/config"
-	"github.com/5ouma/dorg/internal/dock"
+	"github.com/5ouma/dorg/internal/diff"
 	"github.com/5ouma/dorg/internal/utils"
 	"github.com/spf13/cobra"
 )
@@ -22,6 +21,10 @@ func newCheckCmd() *cobra.Command {
 		RunE:  execCheckCmd,
 	}
 	cmd.PersistentFlags().String("file", "dorg.yml", "config file")
+	addFormatFlag(cmd)
+	addTargetFlags(cmd, false)
+	addProfileFlag(cmd)
+	addRemoteFlags(cmd)
 	cmd.PersistentFlags().BoolP("verbose", "V", false, "verbose output")
 	return cmd
 }
@@ -40,19 +43,42 @@ func execCheckCmd(cmd *cobra.Command, args []string) error {
 		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})))
 	}
 
+	target, err := targetFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	opts, err := loadOptions(cmd)
+	if err != nil {
+		return err
+	}
+	fetcher, err := fetcherFromFlags(cmd, file)
+	if err != nil {
+		return err
+	}
+	opts.Read = fetcher.Read
+	if opts.Format, err = formatFromFlags(cmd); err != nil {
+		return err
+	}
+
 	fmt.Println(utils.H1.Render("🔍 Check Login Items"))
 
-	cfg, err := loadFileConfig(file)
+	cfg, err := loadFileConfig(file, target, opts)
 	if err != nil {
 		return err
 	}
 
-	plistCfg, err := loadPlistConfig()
+	plistCfg, err := loadPlistConfig(target.WithConfig(cfg.Target))
 	if err != nil {
 		return err
 	}
 
-	if !bytes.Equal(cfg, plistCfg) {
+	result, err := checkChanges(cfg, plistCfg)
+	if err != nil {
+		return err
+	}
+	if !result.Empty() {
+		fmt.Println(utils.H1.Render("📝 Differences"))
+		diff.Render(os.Stdout, result)
 		return fmt.Errorf("dock items are out-of-date")
 	}
 
@@ -60,35 +86,31 @@ func execCheckCmd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func loadFileConfig(path string) ([]byte, error) {
-	cfg, err := config.Load(path)
-	if err != nil {
-		return nil, err
-	}
-
-	data, err := json.Marshal(cfg)
+// checkChanges returns what a load of cfg would change in the Dock of plistCfg,
+// leaving out what cfg does not manage.
+func checkChanges(cfg, plistCfg config.Config) (diff.Result, error) {
+	mode, err := cfg.ModeFor("")
 	if err != nil {
-		return nil, err
+		return diff.Result{}, err
 	}
-
-	return data, nil
-}
-
-func loadPlistConfig() ([]byte, error) {
-	plist, err := dock.LoadDockPlist()
-	if err != nil {
-		return nil, err
+	if mode == config.ModeEnsure {
+		cfg.Dock.Apps = cfg.Dock.Ensure(plistCfg.Dock.Apps)
+		if len(cfg.Dock.Others) == 0 {
+			plistCfg.Dock.Others = nil
+		}
 	}
-
-	cfg, err := plist.GenerateConfigFromPlist()
-	if err != nil {
-		return nil, err
+	if cfg.Dock.Recents == nil {
+		plistCfg.Dock.Recents = nil
 	}
+	plistCfg.Dock.Settings = plistCfg.Dock.Settings.Managed(cfg.Dock.Settings)
+	plistCfg.HotCorners = plistCfg.HotCorners.Managed(cfg.HotCorners)
+	return diff.Compare(plistCfg, cfg), nil
+}
 
-	data, err := json.Marshal(cfg)
-	if err != nil {
-		return nil, err
-	}
+func loadFileConfig(path string, target command.Target, opts config.LoadOptions) (config.Config, error) {
+	return command.LoadSource(path, target, opts)
+}
 
-	return data, nil
+func loadPlistConfig(target command.Target) (config.Config, error) {
+	return command.LoadSource(command.SourceDock, target, config.LoadOptions{})
 }
@@ -1,12 +1,15 @@
 package cmd
 
 import (
-	"encoding/json"
+	"bytes"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
+	"github.com/5ouma/dorg/internal/command"
 	"github.com/5ouma/dorg/internal/config"
+	"github.com/5ouma/dorg/internal/diff"
 	"github.com/5ouma/dorg/internal/dock"
 	"howett.net/plist"
 )
@@ -17,11 +20,17 @@ func Test_loadFileConfig(t *testing.T) {
 	tests := map[string]struct {
 		content   string
 		path      string
+		profile   string
 		wantError bool
 	}{
-		"valid file":   {content: `dock_items: {apps: ["/Applications/Calculator.app"]}`, wantError: false},
-		"missing file": {path: "/non/existing/path.yml", wantError: true},
-		"invalid yaml": {content: "dock_items: [unclosed", wantError: true},
+		"valid file": {content: `dock_items: {apps: ["/Applications/Calculator.app"]}`, wantError: false},
+		"profile": {
+			content: "dock_items: {apps: [/Applications/Safari.app]}\nprofiles: {dev: {dock_items: {apps: {replace: [/Applications/Calculator.app]}}}}",
+			profile: "dev",
+		},
+		"unknown profile": {content: `dock_items: {apps: ["/Applications/Calculator.app"]}`, profile: "dev", wantError: true},
+		"missing file":    {path: "/non/existing/path.yml", wantError: true},
+		"invalid yaml":    {content: "dock_items: [unclosed", wantError: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -37,16 +46,12 @@ func Test_loadFileConfig(t *testing.T) {
 				path = tc.path
 			}
 
-			data, err := loadFileConfig(path)
+			got, err := loadFileConfig(path, command.Target{}, config.LoadOptions{Profile: tc.profile})
 			if (err != nil) != tc.wantError {
 				t.Fatalf("%s error=%v, wantErr=%v", path, err, tc.wantError)
 			}
 			if err == nil {
-				var got config.Config
-				if err := json.Unmarshal(data, &got); err != nil {
-					t.Fatalf("failed to unmarshal json: %v", err)
-				}
-				if len(got.Dock.Apps) != 1 || got.Dock.Apps[0] != "/Applications/Calculator.app" {
+				if len(got.Dock.Apps) != 1 || got.Dock.Apps[0].Path != "/Applications/Calculator.app" {
 					t.Fatalf("unexpected apps: %#v", got.Dock.Apps)
 				}
 			}
@@ -107,16 +112,12 @@ func Test_loadPlistConfig(t *testing.T) {
 				}
 			}
 
-			data, err := loadPlistConfig()
+			got, err := loadPlistConfig(command.Target{})
 			if (err != nil) != tc.wantError {
-				t.Fatalf("loadPlistConfig() error = %v, wantErr=%v", err, tc.wantError)
+				t.Fatalf("loadPlistConfig(command.Target{}) error = %v, wantErr=%v", err, tc.wantError)
 			}
 			if err == nil {
-				var got config.Config
-				if err := json.Unmarshal(data, &got); err != nil {
-					t.Fatalf("failed to unmarshal json: %v", err)
-				}
-				if len(got.Dock.Apps) != 1 || got.Dock.Apps[0] != "/Applications/Calculator.app" {
+				if len(got.Dock.Apps) != 1 || got.Dock.Apps[0].Path != "/Applications/Calculator.app" {
 					t.Fatalf("unexpected apps: %#v", got.Dock.Apps)
 				}
 				if len(got.Dock.Others) != 1 {
@@ -130,3 +131,87 @@ func Test_loadPlistConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_execCheckCmd(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	plistPath := filepath.Join(tmp, "com.apple.dock.plist")
+	data, err := plist.Marshal(&dock.Plist{
+		PersistentApps:   []dock.PAItem{{TileType: "file-tile", TileData: dock.TileData{FileData: dock.FileData{URLString: "file:///A.app/", URLStringType: 15}}}},
+		PersistentOthers: []dock.POItem{{TileType: "directory-tile", TileData: dock.POTileData{FileData: dock.FileData{URLString: "file:///Users/Shared/", URLStringType: 15}, FileType: 2}}},
+		Magnification:    true,
+		ShowRecents:      true,
+	}, plist.BinaryFormat)
+	if err != nil {
+		t.Fatalf("failed to marshal plist: %v", err)
+	}
+	if err := os.WriteFile(plistPath, data, 0644); err != nil {
+		t.Fatalf("failed to write plist: %v", err)
+	}
+
+	tests := map[string]struct {
+		content string
+		wantErr bool
+	}{
+		"ensure ignores others":      {content: "mode: ensure\ndock_items: {present: [/A.app]}"},
+		"ensure missing app":         {content: "mode: ensure\ndock_items: {present: [/B.app]}", wantErr: true},
+		"ensure with listed others":  {content: "mode: ensure\ndock_items: {present: [/A.app], others: [{path: /Applications}]}", wantErr: true},
+		"exact compares others":      {content: "dock_items: {apps: [/A.app]}", wantErr: true},
+		"exact with the same others": {content: "dock_items: {apps: [/A.app], others: [{path: /Users/Shared}]}"},
+		"settings listed":            {content: "dock_items: {apps: [/A.app], others: [{path: /Users/Shared}], settings: {magnification: false}}", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			file := filepath.Join(t.TempDir(), "dorg.yml")
+			if err := os.WriteFile(file, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("failed to write test file: %v", err)
+			}
+
+			c := newCheckCmd()
+			c.SetOut(new(bytes.Buffer))
+			c.SetErr(new(bytes.Buffer))
+			c.SetArgs([]string{"--file", file, "--home", tmp, "--plist", plistPath})
+			if err := c.Execute(); (err != nil) != tc.wantErr {
+				t.Fatalf("check error = %v, wantErr=%v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkChanges(t *testing.T) {
+	t.Parallel()
+
+	current := config.Config{Dock: config.Dock{
+		Apps:     []config.App{{Path: "/A.app"}, {Path: "/B.app"}},
+		Settings: &config.DockSettings{TileSize: 48},
+	}}
+	tests := map[string]struct {
+		cfg  config.Config
+		want []diff.Kind
+	}{
+		"up-to-date":         {cfg: config.Config{Dock: config.Dock{Apps: []config.App{{Path: "/A.app"}, {Path: "/B.app"}}}}},
+		"app only in dock":   {cfg: config.Config{Dock: config.Dock{Apps: []config.App{{Path: "/A.app"}}}}, want: []diff.Kind{diff.Removed}},
+		"app only in config": {cfg: config.Config{Dock: config.Dock{Apps: []config.App{{Path: "/A.app"}, {Path: "/B.app"}, {Path: "/C.app"}}}}, want: []diff.Kind{diff.Added}},
+		"setting changed":    {cfg: config.Config{Dock: config.Dock{Apps: []config.App{{Path: "/A.app"}, {Path: "/B.app"}}, Settings: &config.DockSettings{TileSize: 64}}}, want: []diff.Kind{diff.Modified}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := checkChanges(tc.cfg, current)
+			if err != nil {
+				t.Fatalf("checkChanges error: %v", err)
+			}
+			var got []diff.Kind
+			for _, c := range result.Changes {
+				got = append(got, c.Kind)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("kinds = %v, want %v (%+v)", got, tc.want, result.Changes)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/5ouma/dorg/internal/utils"
@@ -21,8 +25,8 @@ func Test_New_has_subcommands_and_version(t *testing.T) {
 	for _, c := range cmd.Commands() {
 		names[c.Name()] = true
 	}
-	if !names["load"] || !names["save"] {
-		t.Fatalf("expected load and save subcommands present")
+	if !names["load"] || !names["save"] || !names["diff"] {
+		t.Fatalf("expected load, save and diff subcommands present")
 	}
 }
 
@@ -82,3 +86,176 @@ func Test_execCommands(t *testing.T) {
 		})
 	}
 }
+
+func Test_execDiffCmd(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	from := filepath.Join(tmp, "from.yml")
+	to := filepath.Join(tmp, "to.yml")
+	if err := os.WriteFile(from, []byte(`dock_items: {apps: ["/A.app", "/B.app"]}`), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	if err := os.WriteFile(to, []byte(`dock_items: {apps: ["/B.app", "/A.app"]}`), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	toTOML := filepath.Join(tmp, "to.conf")
+	if err := os.WriteFile(toTOML, []byte("[dock_items]\napps = [\"/B.app\", \"/A.app\"]\n"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	tests := map[string]struct {
+		args    []string
+		stdin   string
+		want    string
+		wantErr bool
+	}{
+		"unified":        {args: []string{"--output-format", "unified", from, to}, want: "@@ apps @@", wantErr: false},
+		"json":           {args: []string{"--output-format", "json", from, to}, want: `"kind": "moved"`, wantErr: false},
+		"side-by-side":   {args: []string{"--output-format", "side-by-side", from, to}, want: "[apps]", wantErr: false},
+		"unknown format": {args: []string{"--output-format", "xml", from, to}, wantErr: true},
+		"standard input": {args: []string{"--output-format", "json", "--format", "toml", "-", toTOML}, stdin: "[dock_items]\napps = [\"/A.app\", \"/B.app\"]\n", want: `"kind": "moved"`},
+		"checksum":       {args: []string{"--checksum", "sha256:00", from, to}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newDiffCmd()
+			out := new(bytes.Buffer)
+			c.SetOut(out)
+			c.SetErr(new(bytes.Buffer))
+			c.SetIn(strings.NewReader(tc.stdin))
+			c.SetArgs(tc.args)
+			err := c.Execute()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("diff error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if !strings.Contains(out.String(), tc.want) {
+				t.Fatalf("output missing %q:\n%s", tc.want, out.String())
+			}
+		})
+	}
+}
+
+func Test_execValidateCmd(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	tests := map[string]struct {
+		content string
+		wantErr bool
+	}{
+		"valid":   {content: "dock_items: {apps: [/Applications/Safari.app]}", wantErr: false},
+		"invalid": {content: "dock_items: {apps: [/Applications/Safari.app], extra: 1}", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			file := filepath.Join(tmp, strings.ReplaceAll(name, " ", "_")+".yml")
+			if err := os.WriteFile(file, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("failed to write test file: %v", err)
+			}
+
+			c := newValidateCmd()
+			c.SetOut(new(bytes.Buffer))
+			c.SetErr(new(bytes.Buffer))
+			c.SetArgs([]string{"--file", file, "--skip-app-check"})
+			if err := c.Execute(); (err != nil) != tc.wantErr {
+				t.Fatalf("validate error = %v, wantErr=%v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func Test_execConfigResolveCmd(t *testing.T) {
+	tmp := t.TempDir()
+	file := filepath.Join(tmp, "dorg.yml")
+	content := "dock_items:\n  apps: [/A.app]\nprofiles:\n  dev:\n    dock_items:\n      apps: {append: [/B.app]}\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	tests := map[string]struct {
+		args    []string
+		env     string
+		stdin   string
+		want    string
+		wantErr bool
+	}{
+		"base":              {args: nil, want: "dock_items:\n  apps: [/A.app]\n"},
+		"profile":           {args: []string{"--profile", "dev"}, want: "dock_items:\n  apps:\n    - /A.app\n    - /B.app\n"},
+		"profile env":       {env: "dev", want: "dock_items:\n  apps:\n    - /A.app\n    - /B.app\n"},
+		"unknown profile":   {args: []string{"--profile", "ops"}, wantErr: true},
+		"stdin":             {args: []string{"--file", "-"}, stdin: "dock_items:\n  apps: [/C.app]\n", want: "dock_items:\n  apps: [/C.app]\n"},
+		"checksum mismatch": {args: []string{"--checksum", "sha256:00"}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("DORG_PROFILE", tc.env)
+
+			c := newConfigCmd()
+			out := new(bytes.Buffer)
+			c.SetOut(out)
+			c.SetErr(new(bytes.Buffer))
+			c.SetIn(strings.NewReader(tc.stdin))
+			c.SetArgs(append([]string{"resolve", "--file", file}, tc.args...))
+			err := c.Execute()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("config resolve error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if err == nil && out.String() != tc.want {
+				t.Fatalf("config resolve output:\n%s\nwant:\n%s", out.String(), tc.want)
+			}
+		})
+	}
+}
+
+func Test_execConfigRenderCmd(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	tests := map[string]struct {
+		file    string
+		content string
+		home    string
+		want    string
+		wantErr bool
+	}{
+		"template":   {file: "dorg.yml.tmpl", content: "arch: {{ if .Arch }}set{{ end }}\n", want: "arch: set\n"},
+		"home":       {file: "home.yml.tmpl", content: "home: {{ .Home }}\n", home: tmp, want: "home: " + tmp + "\n"},
+		"plain yaml": {file: "dorg.yml", content: "label: \"{{ .Arch }}\"\n", want: "label: \"{{ .Arch }}\"\n"},
+		"bad":        {file: "bad.yml.tmpl", content: "{{ .Shell }}\n", wantErr: true},
+		"missing":    {file: "missing.yml.tmpl", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			file := filepath.Join(tmp, tc.file)
+			if tc.content != "" {
+				if err := os.WriteFile(file, []byte(tc.content), 0644); err != nil {
+					t.Fatalf("failed to write test file: %v", err)
+				}
+			}
+
+			c := newConfigCmd()
+			out := new(bytes.Buffer)
+			c.SetOut(out)
+			c.SetErr(new(bytes.Buffer))
+			args := []string{"render", "--file", file}
+			if tc.home != "" {
+				args = append(args, "--home", tc.home)
+			}
+			c.SetArgs(args)
+			err := c.Execute()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("config render error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if err == nil && out.String() != tc.want {
+				t.Fatalf("config render output:\n%s\nwant:\n%s", out.String(), tc.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"github.com/5ouma/dorg/internal/runner"
 	"github.com/5ouma/dorg/internal/utils"
 	"github.com/spf13/cobra"
 )
 
+// dockRunner executes defaults, launchctl and killall for the commands that write the Dock.
+var dockRunner runner.Runner = runner.Exec{}
+
 func New() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:          "dorg",
@@ -17,9 +21,15 @@ func New() *cobra.Command {
 	cmd.SetVersionTemplate("🚥 {{.Use}} {{.Version}}\n")
 	cmd.SetErrPrefix(" 🚨")
 	cmd.AddCommand(
+		newBackupCmd(),
 		newCheckCmd(),
+		newConfigCmd(),
+		newConvertCmd(),
+		newDiffCmd(),
 		newLoadCmd(),
+		newRestoreCmd(),
 		newSaveCmd(),
+		newValidateCmd(),
 	)
 
 	return cmd
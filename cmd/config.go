@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/5ouma/dorg/internal/config"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect config files",
+		Long:  "⚙️ Inspect config files",
+		Args:  cobra.NoArgs,
+	}
+	cmd.PersistentFlags().String("file", "dorg.yml", "config file")
+	addFormatFlag(cmd)
+	cmd.PersistentFlags().String("home", "", "home directory templates see as .Home (or $DORG_HOME)")
+	addProfileFlag(cmd)
+	addRemoteFlags(cmd)
+	cmd.PersistentFlags().BoolP("verbose", "V", false, "verbose output")
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "resolve",
+			Short: "Print the effective config",
+			Long:  "⚙️ Print the config file with the selected profile and the overlays matching this Mac merged in",
+			Args:  cobra.NoArgs,
+			RunE:  execConfigResolveCmd,
+		},
+		&cobra.Command{
+			Use:   "render",
+			Short: "Print the rendered config template",
+			Long:  "⚙️ Print the config file with its template expanded for this Mac",
+			Args:  cobra.NoArgs,
+			RunE:  execConfigRenderCmd,
+		},
+	)
+	return cmd
+}
+
+func addProfileFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("profile", "", "config profile to apply (or $DORG_PROFILE)")
+}
+
+func addFormatFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("format", "", "config file format: yaml, json or toml (default by extension)")
+}
+
+func formatFromFlags(cmd *cobra.Command) (config.Format, error) {
+	name, err := cmd.Flags().GetString("format")
+	if err != nil || name == "" {
+		return "", err
+	}
+	return config.ParseFormat(name)
+}
+
+// loadOptions reads the profile and the target home from the flags, falling
+// back to the environment.
+func loadOptions(cmd *cobra.Command) (config.LoadOptions, error) {
+	opts := config.LoadOptions{Profile: os.Getenv("DORG_PROFILE")}
+	if cmd.Flags().Changed("profile") {
+		profile, err := cmd.Flags().GetString("profile")
+		if err != nil {
+			return config.LoadOptions{}, err
+		}
+		opts.Profile = profile
+	}
+	target, err := targetFromFlags(cmd)
+	if err != nil {
+		return config.LoadOptions{}, err
+	}
+	l, err := target.Location()
+	if err != nil {
+		return config.LoadOptions{}, err
+	}
+	opts.Home = l.Home
+	return opts, nil
+}
+
+func execConfigResolveCmd(cmd *cobra.Command, args []string) error {
+	file, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+	if err := setVerbose(cmd); err != nil {
+		return err
+	}
+	opts, err := loadOptions(cmd)
+	if err != nil {
+		return err
+	}
+	fetcher, err := fetcherFromFlags(cmd, file)
+	if err != nil {
+		return err
+	}
+	opts.Read = fetcher.Read
+	if opts.Format, err = formatFromFlags(cmd); err != nil {
+		return err
+	}
+
+	doc, err := config.ResolveFile(file, opts)
+	if err != nil {
+		return errors.Wrapf(err, "unable to resolve %s", file)
+	}
+
+	enc := yaml.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return errors.Wrap(err, "unable to encode YAML")
+	}
+	return enc.Close()
+}
+
+func execConfigRenderCmd(cmd *cobra.Command, args []string) error {
+	file, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+	if err := setVerbose(cmd); err != nil {
+		return err
+	}
+	opts, err := loadOptions(cmd)
+	if err != nil {
+		return err
+	}
+	fetcher, err := fetcherFromFlags(cmd, file)
+	if err != nil {
+		return err
+	}
+	opts.Read = fetcher.Read
+
+	data, err := config.RenderFile(file, opts)
+	if err != nil {
+		return errors.Wrapf(err, "unable to render %s", file)
+	}
+	_, err = cmd.OutOrStdout().Write(data)
+	return err
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/5ouma/dorg/internal/config"
+	"github.com/5ouma/dorg/internal/remote"
+	"github.com/5ouma/dorg/internal/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newConvertCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "convert <input> [output]",
+		Short: "Convert config files",
+		Long:  "🔄 Convert a config file between YAML, JSON and TOML, told by the file extensions (\"-\" for standard input and output)",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  execConvertCmd,
+	}
+	cmd.PersistentFlags().String("from", "", "input format: yaml, json or toml (default by extension)")
+	cmd.PersistentFlags().String("to", "", "output format: yaml, json or toml (default by extension)")
+	addRemoteFlags(cmd)
+	cmd.PersistentFlags().BoolP("verbose", "V", false, "verbose output")
+	return cmd
+}
+
+func execConvertCmd(cmd *cobra.Command, args []string) error {
+	if err := setVerbose(cmd); err != nil {
+		return err
+	}
+	input, output := args[0], remote.Stdin
+	if len(args) > 1 {
+		output = args[1]
+	}
+	from, err := formatFlag(cmd, "from", input)
+	if err != nil {
+		return err
+	}
+	to, err := formatFlag(cmd, "to", output)
+	if err != nil {
+		return err
+	}
+	fetcher, err := fetcherFromFlags(cmd, input)
+	if err != nil {
+		return err
+	}
+
+	// Templates are converted as they are, so that the output stays one.
+	data, err := fetcher.Read(input)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read %s", input)
+	}
+	doc, err := config.Decode(from, data)
+	if err != nil {
+		return errors.Wrapf(err, "unable to parse %s", input)
+	}
+	out, err := config.Encode(to, doc)
+	if err != nil {
+		return errors.Wrapf(err, "unable to encode %s", to)
+	}
+
+	if output == remote.Stdin {
+		_, err := cmd.OutOrStdout().Write(out)
+		return err
+	}
+	if err := os.WriteFile(output, out, 0644); err != nil {
+		return err
+	}
+	fmt.Println(utils.Msg.Render("✅", input, "->", output))
+	return nil
+}
+
+// formatFlag returns the format given with the flag name, or the one of file.
+func formatFlag(cmd *cobra.Command, name, file string) (config.Format, error) {
+	value, err := cmd.Flags().GetString(name)
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return config.FormatOf(file), nil
+	}
+	return config.ParseFormat(value)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_execConvertCmd(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	yml := filepath.Join(tmp, "dorg.yml")
+	if err := os.WriteFile(yml, []byte("dock_items:\n  apps: [/A.app, {spacer: small}]\n  settings: {tilesize: 48}\n"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	tmpl := filepath.Join(tmp, "dorg.yml.tmpl")
+	if err := os.WriteFile(tmpl, []byte("dock_items:\n  apps: [\"/Users/{{ .User }}/Applications/A.app\"]\n"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	tests := map[string]struct {
+		args    []string
+		stdin   string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		"yaml to json": {
+			args:   []string{yml, filepath.Join(tmp, "dorg.json")},
+			output: filepath.Join(tmp, "dorg.json"),
+			want:   "{\n  \"dock_items\": {\n    \"apps\": [\n      \"/A.app\",\n      {\n        \"spacer\": \"small\"\n      }\n    ],\n    \"settings\": {\n      \"tilesize\": 48\n    }\n  }\n}\n",
+		},
+		"yaml to toml on standard output": {
+			args: []string{yml, "--to", "toml"},
+			want: "[dock_items]\n  apps = [\"/A.app\", {spacer = \"small\"}]\n  [dock_items.settings]\n    tilesize = 48\n",
+		},
+		"toml from standard input": {
+			args:  []string{"-", "--from", "toml"},
+			stdin: "[dock_items]\napps = [\"/A.app\"]\n",
+			want:  "dock_items:\n  apps:\n    - /A.app\n",
+		},
+		"template is not rendered": {
+			args: []string{tmpl, "--to", "json"},
+			want: "{\n  \"dock_items\": {\n    \"apps\": [\n      \"/Users/{{ .User }}/Applications/A.app\"\n    ]\n  }\n}\n",
+		},
+		"unknown format": {args: []string{yml, "--to", "ini"}, wantErr: true},
+		"invalid input":  {args: []string{"-", "--from", "json"}, stdin: "{", wantErr: true},
+		"missing input":  {args: []string{filepath.Join(tmp, "missing.yml")}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newConvertCmd()
+			out := new(bytes.Buffer)
+			c.SetOut(out)
+			c.SetErr(new(bytes.Buffer))
+			c.SetIn(strings.NewReader(tc.stdin))
+			c.SetArgs(tc.args)
+			err := c.Execute()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("convert error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got := out.String()
+			if tc.output != "" {
+				data, err := os.ReadFile(tc.output)
+				if err != nil {
+					t.Fatalf("failed to read output: %v", err)
+				}
+				got = string(data)
+			}
+			if got != tc.want {
+				t.Fatalf("convert output:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/5ouma/dorg/internal/command"
+	"github.com/5ouma/dorg/internal/diff"
+	"github.com/spf13/cobra"
+)
+
+func newDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [from] [to]",
+		Short: "Diff Dock sources",
+		Long:  "📝 Show the differences between two Dock sources (config file, saved plist, \"backup:<id>\" or \"dock\")",
+		Args:  cobra.MaximumNArgs(2),
+		RunE:  execDiffCmd,
+	}
+	cmd.PersistentFlags().String("file", "dorg.yml", "config file used when [from] is omitted")
+	addFormatFlag(cmd)
+	addTargetFlags(cmd, false)
+	addProfileFlag(cmd)
+	addRemoteFlags(cmd)
+	cmd.PersistentFlags().String("output-format", string(diff.FormatUnified), "output format (unified, json, side-by-side)")
+	cmd.PersistentFlags().BoolP("verbose", "V", false, "verbose output")
+	return cmd
+}
+
+func execDiffCmd(cmd *cobra.Command, args []string) error {
+	file, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+	formatName, err := cmd.Flags().GetString("output-format")
+	if err != nil {
+		return err
+	}
+	verbose, err := cmd.Flags().GetBool("verbose")
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	}
+
+	format, err := diff.ParseFormat(formatName)
+	if err != nil {
+		return err
+	}
+	target, err := targetFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	opts, err := loadOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	from, to := file, command.SourceDock
+	if len(args) > 0 {
+		from = args[0]
+	}
+	if len(args) > 1 {
+		to = args[1]
+	}
+	fetcher, err := fetcherFromFlags(cmd, from)
+	if err != nil {
+		return err
+	}
+	opts.Read = fetcher.Read
+	if opts.Format, err = formatFromFlags(cmd); err != nil {
+		return err
+	}
+
+	fromCfg, err := command.LoadSource(from, target, opts)
+	if err != nil {
+		return err
+	}
+	toCfg, err := command.LoadSource(to, target, opts)
+	if err != nil {
+		return err
+	}
+
+	return diff.Write(cmd.OutOrStdout(), format, from, to, fromCfg, toCfg)
+}
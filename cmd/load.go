@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/5ouma/dorg/internal/command"
+	"github.com/5ouma/dorg/internal/config"
 	"github.com/5ouma/dorg/internal/utils"
 	"github.com/spf13/cobra"
 )
@@ -14,11 +15,22 @@ func newLoadCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "load",
 		Short: "Load Dock items",
-		Long:  "📂 Load Dock items and settings from YAML file",
+		Long:  "📂 Load Dock items and settings from a YAML, JSON or TOML file",
 		Args:  cobra.NoArgs,
 		RunE:  execLoadCmd,
 	}
 	cmd.PersistentFlags().String("file", "dorg.yml", "config file")
+	addFormatFlag(cmd)
+	cmd.PersistentFlags().Bool("dry-run", false, "show the planned changes without touching the Dock")
+	cmd.PersistentFlags().String("output", "", "write the planned Dock plist to this file instead of applying it")
+	cmd.PersistentFlags().Bool("skip-app-check", false, "do not require the listed apps to be installed")
+	cmd.PersistentFlags().String("mode", "", "override the config mode: exact or ensure")
+	cmd.PersistentFlags().Int64("seed", 0, "seed for the GUIDs of new Dock tiles")
+	cmd.PersistentFlags().Bool("clear-recents", false, "empty the recent applications section unless the config lists recents")
+	addTargetFlags(cmd, true)
+	addProfileFlag(cmd)
+	addRemoteFlags(cmd)
+	addBackupFlags(cmd)
 	cmd.PersistentFlags().BoolP("verbose", "V", false, "verbose output")
 	return cmd
 }
@@ -28,6 +40,14 @@ func execLoadCmd(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
 	verbose, err := cmd.Flags().GetBool("verbose")
 	if err != nil {
 		return err
@@ -37,10 +57,60 @@ func execLoadCmd(cmd *cobra.Command, args []string) error {
 		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})))
 	}
 
+	skipApps, err := cmd.Flags().GetBool("skip-app-check")
+	if err != nil {
+		return err
+	}
+	clearRecents, err := cmd.Flags().GetBool("clear-recents")
+	if err != nil {
+		return err
+	}
+	seed, err := cmd.Flags().GetInt64("seed")
+	if err != nil {
+		return err
+	}
+	mode, err := cmd.Flags().GetString("mode")
+	if err != nil {
+		return err
+	}
+	store, err := backupStore(cmd)
+	if err != nil {
+		return err
+	}
+	target, err := targetFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	opts, err := loadOptions(cmd)
+	if err != nil {
+		return err
+	}
+	fetcher, err := fetcherFromFlags(cmd, file)
+	if err != nil {
+		return err
+	}
+	format, err := formatFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
 	cfg := &command.Config{
 		Cmd:      cmd.Use,
 		File:     file,
 		LogLevel: utils.SetLogLevel(verbose),
+		Backup:   store,
+		Runner:   dockRunner,
+		Target:   target,
+		DryRun:   dryRun,
+		Output:   output,
+		SkipApps: skipApps,
+
+		ClearRecents: clearRecents,
+		Seed:         seed,
+		Mode:         config.Mode(mode),
+		Profile:      opts.Profile,
+		Read:         fetcher.Read,
+		Format:       format,
 	}
 
 	if err := cfg.Verify(); err != nil {
@@ -51,6 +121,9 @@ func execLoadCmd(cmd *cobra.Command, args []string) error {
 	if err := command.LoadConfig(cfg); err != nil {
 		return err
 	}
+	if dryRun || output != "" {
+		return nil
+	}
 	fmt.Println(utils.Msg.Render("✅ Dock settings loaded successfully"))
 	return nil
 }
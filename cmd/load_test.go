@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/5ouma/dorg/internal/dock"
+	"github.com/5ouma/dorg/internal/runner"
+	"github.com/5ouma/dorg/internal/utils"
+	"howett.net/plist"
+)
+
+func setupDock(t *testing.T, r runner.Runner) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+	t.Setenv("DORG_BACKUP_DIR", "")
+	t.Setenv("DORG_BACKUP_KEEP", "")
+
+	oldRunner, oldDelay := dockRunner, utils.DockRestartDelay
+	dockRunner, utils.DockRestartDelay = r, 0
+	t.Cleanup(func() {
+		dockRunner, utils.DockRestartDelay = oldRunner, oldDelay
+	})
+
+	prefsDir := filepath.Join(home, "Library", "Preferences")
+	if err := os.MkdirAll(prefsDir, 0755); err != nil {
+		t.Fatalf("failed to create prefs dir: %v", err)
+	}
+	data, err := plist.Marshal(map[string]any{
+		"orientation":       "left",
+		"persistent-apps":   []any{},
+		"persistent-others": []any{},
+	}, plist.BinaryFormat)
+	if err != nil {
+		t.Fatalf("failed to marshal plist: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(prefsDir, "com.apple.dock.plist"), data, 0644); err != nil {
+		t.Fatalf("failed to write plist: %v", err)
+	}
+
+	file := filepath.Join(home, "dorg.yml")
+	content := `dock_items:
+  apps:
+    - /Applications/Safari.app
+  others:
+    - path: ~/Downloads
+      sort: 1
+  settings:
+    autohide: true
+`
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return file
+}
+
+func Test_LoadCmd_EndToEnd(t *testing.T) {
+	tests := map[string]struct {
+		failOn   string
+		timeout  string
+		wantErr  bool
+		wantCmds []string
+	}{
+		"success": {
+			wantErr: false,
+			wantCmds: []string{
+				"/bin/launchctl unload /System/Library/LaunchAgents/com.apple.Dock.agent.plist",
+				"/usr/bin/defaults import com.apple.dock",
+				"/bin/launchctl load /System/Library/LaunchAgents/com.apple.Dock.agent.plist",
+				"/bin/launchctl start com.apple.Dock.agent",
+				"killall Dock",
+			},
+		},
+		"unload fails": {
+			failOn:   "/bin/launchctl unload",
+			wantErr:  true,
+			wantCmds: []string{"/bin/launchctl unload /System/Library/LaunchAgents/com.apple.Dock.agent.plist"},
+		},
+		"import fails": {
+			failOn:  "/usr/bin/defaults import",
+			wantErr: true,
+			wantCmds: []string{
+				"/bin/launchctl unload /System/Library/LaunchAgents/com.apple.Dock.agent.plist",
+				"/usr/bin/defaults import com.apple.dock",
+			},
+		},
+		"killall times out": {
+			timeout: "killall",
+			wantErr: true,
+			wantCmds: []string{
+				"/bin/launchctl unload /System/Library/LaunchAgents/com.apple.Dock.agent.plist",
+				"/usr/bin/defaults import com.apple.dock",
+				"/bin/launchctl load /System/Library/LaunchAgents/com.apple.Dock.agent.plist",
+				"/bin/launchctl start com.apple.Dock.agent",
+				"killall Dock",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var imported []byte
+			rec := &runner.Recorder{Respond: func(ctx context.Context, c runner.Call) (string, error) {
+				switch {
+				case tc.failOn != "" && strings.HasPrefix(c.String(), tc.failOn):
+					return "", errors.New("boom")
+				case tc.timeout != "":
+					if _, err := runner.TimeOut(tc.timeout)(ctx, c); err != nil {
+						return "", err
+					}
+				}
+				if c.Cmd == "/usr/bin/defaults" {
+					data, err := os.ReadFile(c.Args[len(c.Args)-1])
+					if err != nil {
+						return "", err
+					}
+					imported = data
+				}
+				return "", nil
+			}}
+			file := setupDock(t, rec)
+
+			c := New()
+			c.SetOut(new(bytes.Buffer))
+			c.SetErr(new(bytes.Buffer))
+			c.SetArgs([]string{"load", "--file", file, "--skip-app-check"})
+			if err := c.Execute(); (err != nil) != tc.wantErr {
+				t.Fatalf("load error = %v, wantErr=%v", err, tc.wantErr)
+			}
+
+			got := rec.Commands()
+			if len(got) != len(tc.wantCmds) {
+				t.Fatalf("commands = %v, want %v", got, tc.wantCmds)
+			}
+			for i, want := range tc.wantCmds {
+				if !strings.HasPrefix(got[i], want) {
+					t.Fatalf("command %d = %s, want %s", i, got[i], want)
+				}
+			}
+
+			if imported != nil {
+				p, err := dock.ParsePlist(imported)
+				if err != nil {
+					t.Fatalf("failed to parse imported plist: %v", err)
+				}
+				conf, err := p.GenerateConfigFromPlist()
+				if err != nil {
+					t.Fatalf("GenerateConfigFromPlist error: %v", err)
+				}
+				if len(conf.Dock.Apps) != 1 || conf.Dock.Apps[0].Path != "/Applications/Safari.app" || len(conf.Dock.Others) != 1 || !conf.Dock.Settings.AutoHide {
+					t.Fatalf("unexpected imported config: %+v", conf.Dock)
+				}
+				raw := map[string]any{}
+				if _, err := plist.Unmarshal(imported, &raw); err != nil {
+					t.Fatalf("failed to decode imported plist: %v", err)
+				}
+				if raw["orientation"] != "left" {
+					t.Fatalf("orientation = %v, want left", raw["orientation"])
+				}
+			}
+
+			backups, err := os.ReadDir(filepath.Join(os.Getenv("XDG_STATE_HOME"), "dorg", "backups"))
+			if err != nil || len(backups) != 1 {
+				t.Fatalf("expected one backup, got %v (err=%v)", backups, err)
+			}
+		})
+	}
+}
+
+func Test_LoadCmd_DryRun(t *testing.T) {
+	rec := &runner.Recorder{}
+	file := setupDock(t, rec)
+	output := filepath.Join(filepath.Dir(file), "planned.plist")
+
+	c := New()
+	c.SetOut(new(bytes.Buffer))
+	c.SetErr(new(bytes.Buffer))
+	c.SetArgs([]string{"load", "--file", file, "--skip-app-check", "--dry-run", "--output", output})
+	if err := c.Execute(); err != nil {
+		t.Fatalf("load --dry-run error: %v", err)
+	}
+	if cmds := rec.Commands(); len(cmds) != 0 {
+		t.Fatalf("dry run executed commands: %v", cmds)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Fatalf("planned plist not written: %v", err)
+	}
+}
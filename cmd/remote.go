@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"github.com/5ouma/dorg/internal/remote"
+	"github.com/spf13/cobra"
+)
+
+func addRemoteFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("checksum", "", "SHA-256 digest the config file must have (sha256:<hex>)")
+	cmd.PersistentFlags().Duration("timeout", remote.DefaultTimeout, "timeout for fetching a config file URL")
+}
+
+// fetcherFromFlags returns the fetcher that reads file, which may also be a
+// URL or "-" for standard input.
+func fetcherFromFlags(cmd *cobra.Command, file string) (*remote.Fetcher, error) {
+	checksum, err := cmd.Flags().GetString("checksum")
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil {
+		return nil, err
+	}
+
+	f := remote.NewFetcher(timeout)
+	f.Stdin = cmd.InOrStdin()
+	if checksum != "" {
+		f.Checksums = map[string]string{file: checksum}
+	}
+	return f, nil
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/5ouma/dorg/internal/command"
+	"github.com/5ouma/dorg/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func newRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <id|latest>",
+		Short: "Restore Dock backup",
+		Long:  "⏪ Restore the Dock preferences from a backup",
+		Args:  cobra.ExactArgs(1),
+		RunE:  execRestoreCmd,
+	}
+	addTargetFlags(cmd, true)
+	addBackupFlags(cmd)
+	cmd.PersistentFlags().BoolP("verbose", "V", false, "verbose output")
+	return cmd
+}
+
+func execRestoreCmd(cmd *cobra.Command, args []string) error {
+	if err := setVerbose(cmd); err != nil {
+		return err
+	}
+	store, err := backupStore(cmd)
+	if err != nil {
+		return err
+	}
+	target, err := targetFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg := &command.Config{
+		Cmd:    cmd.Use,
+		Backup: store,
+		Runner: dockRunner,
+		Target: target,
+	}
+
+	fmt.Println(utils.H1.Render("⏪ Restore Dock settings"))
+	if err := command.RestoreBackup(cfg, args[0]); err != nil {
+		return err
+	}
+	fmt.Println(utils.Msg.Render("✅ Dock settings restored successfully"))
+	return nil
+}
@@ -14,11 +14,14 @@ func newSaveCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "save",
 		Short: "Save Dock items",
-		Long:  "💾 Save the current Dock items and settings to YAML file",
+		Long:  "💾 Save the current Dock items and settings to a YAML, JSON or TOML file",
 		Args:  cobra.NoArgs,
 		RunE:  execSaveCmd,
 	}
 	cmd.PersistentFlags().String("file", "dorg.yml", "config file")
+	addFormatFlag(cmd)
+	cmd.PersistentFlags().Bool("bundle-ids", false, "save apps as bundle IDs instead of paths")
+	addTargetFlags(cmd, false)
 	cmd.PersistentFlags().BoolP("verbose", "V", false, "verbose output")
 	return cmd
 }
@@ -37,10 +40,26 @@ func execSaveCmd(cmd *cobra.Command, args []string) error {
 		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})))
 	}
 
+	bundleIDs, err := cmd.Flags().GetBool("bundle-ids")
+	if err != nil {
+		return err
+	}
+	target, err := targetFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	format, err := formatFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
 	cfg := &command.Config{
-		Cmd:      cmd.Use,
-		File:     file,
-		LogLevel: utils.SetLogLevel(verbose),
+		Cmd:       cmd.Use,
+		File:      file,
+		LogLevel:  utils.SetLogLevel(verbose),
+		Target:    target,
+		BundleIDs: bundleIDs,
+		Format:    format,
 	}
 
 	if err := cfg.Verify(); err != nil {
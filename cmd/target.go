@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/5ouma/dorg/internal/command"
+	"github.com/spf13/cobra"
+)
+
+func addTargetFlags(cmd *cobra.Command, writes bool) {
+	cmd.PersistentFlags().String("home", "", "home directory whose Dock to use (or $DORG_HOME)")
+	cmd.PersistentFlags().String("plist", "", "Dock plist path (default <home>/Library/Preferences/com.apple.dock.plist, or $DORG_PLIST)")
+	if writes {
+		cmd.PersistentFlags().Bool("offline", false, "write the plist file directly instead of through defaults and launchctl (or $DORG_OFFLINE)")
+	}
+}
+
+// targetFromFlags resolves the target from the flags, falling back to the environment.
+func targetFromFlags(cmd *cobra.Command) (command.Target, error) {
+	t, err := command.TargetFromEnv()
+	if err != nil {
+		return command.Target{}, err
+	}
+
+	if cmd.Flags().Changed("home") {
+		if t.Home, err = cmd.Flags().GetString("home"); err != nil {
+			return command.Target{}, err
+		}
+	}
+	if cmd.Flags().Changed("plist") {
+		if t.Plist, err = cmd.Flags().GetString("plist"); err != nil {
+			return command.Target{}, err
+		}
+	}
+	if cmd.Flags().Lookup("offline") != nil && cmd.Flags().Changed("offline") {
+		if t.Offline, err = cmd.Flags().GetBool("offline"); err != nil {
+			return command.Target{}, err
+		}
+	}
+	return t, nil
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/5ouma/dorg/internal/config"
+	"github.com/5ouma/dorg/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func newValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate config file",
+		Long:  "🩺 Validate the config file and report every problem with its line and column",
+		Args:  cobra.NoArgs,
+		RunE:  execValidateCmd,
+	}
+	cmd.PersistentFlags().String("file", "dorg.yml", "config file")
+	addFormatFlag(cmd)
+	cmd.PersistentFlags().Bool("skip-app-check", false, "do not require the listed apps to be installed")
+	addTargetFlags(cmd, false)
+	addRemoteFlags(cmd)
+	cmd.PersistentFlags().BoolP("verbose", "V", false, "verbose output")
+	return cmd
+}
+
+func execValidateCmd(cmd *cobra.Command, args []string) error {
+	file, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+	skipApps, err := cmd.Flags().GetBool("skip-app-check")
+	if err != nil {
+		return err
+	}
+	if err := setVerbose(cmd); err != nil {
+		return err
+	}
+	target, err := targetFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	l, err := target.Location()
+	if err != nil {
+		return err
+	}
+	fetcher, err := fetcherFromFlags(cmd, file)
+	if err != nil {
+		return err
+	}
+	format, err := formatFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(utils.H1.Render("🩺 Validate config file"))
+	problems, err := config.Validate(file, config.ValidateOptions{Home: l.Home, SkipApps: skipApps, Read: fetcher.Read, Format: format})
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Fprintln(cmd.OutOrStdout(), utils.RemovedItem.Render(), p.Error())
+		}
+		return fmt.Errorf("%d problem(s) found in %s", len(problems), file)
+	}
+
+	fmt.Println(utils.Msg.Render("✅", file, "is valid"))
+	return nil
+}
@@ -0,0 +1,183 @@
+package apps
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"howett.net/plist"
+)
+
+var (
+	ErrNotFound  = errors.New("app not found")
+	ErrAmbiguous = errors.New("app is ambiguous")
+)
+
+// DefaultDirs are the application directories searched for bundle IDs and
+// names. An entry matching apps in more than one of them is ambiguous. "~" is
+// the target home directory.
+var DefaultDirs = []string{
+	"/Applications",
+	"/Applications/Utilities",
+	"~/Applications",
+	"/System/Applications",
+	"/System/Applications/Utilities",
+}
+
+var bundleIDPattern = regexp.MustCompile(`^[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)+$`)
+
+type App struct {
+	Path     string
+	BundleID string
+	Name     string
+}
+
+type info struct {
+	BundleID    string `plist:"CFBundleIdentifier"`
+	Name        string `plist:"CFBundleName"`
+	DisplayName string `plist:"CFBundleDisplayName"`
+}
+
+// Resolver finds installed apps by bundle ID or name. The application
+// directories are scanned once and cached for the lifetime of the Resolver.
+type Resolver struct {
+	Dirs []string
+
+	once sync.Once
+	apps []App
+	err  error
+}
+
+func NewResolver(home string) *Resolver {
+	dirs := make([]string, len(DefaultDirs))
+	for i, dir := range DefaultDirs {
+		if after, ok := strings.CutPrefix(dir, "~/"); ok {
+			dir = filepath.Join(home, after)
+		}
+		dirs[i] = dir
+	}
+	return &Resolver{Dirs: dirs}
+}
+
+// IsPath reports whether an apps entry is a path rather than a bundle ID or name.
+func IsPath(entry string) bool {
+	return strings.HasPrefix(entry, "/") || strings.HasPrefix(entry, "~") || strings.HasPrefix(entry, "file://") || strings.HasPrefix(entry, "$")
+}
+
+// IsBundleID reports whether an apps entry looks like a reverse-DNS bundle identifier.
+func IsBundleID(entry string) bool {
+	return !IsPath(entry) && !strings.HasSuffix(entry, ".app") && bundleIDPattern.MatchString(entry)
+}
+
+// Resolve turns a bundle ID or app name into the app's path. Paths and blank
+// entries are returned unchanged.
+func (r *Resolver) Resolve(entry string) (string, error) {
+	if strings.TrimSpace(entry) == "" || IsPath(entry) {
+		return entry, nil
+	}
+
+	apps, err := r.index()
+	if err != nil {
+		return "", err
+	}
+
+	var matches []string
+	if IsBundleID(entry) {
+		for _, app := range apps {
+			if strings.EqualFold(app.BundleID, entry) {
+				matches = append(matches, app.Path)
+			}
+		}
+	} else {
+		name := strings.TrimSuffix(entry, ".app")
+		for _, app := range apps {
+			if strings.EqualFold(app.Name, name) || strings.EqualFold(strings.TrimSuffix(filepath.Base(app.Path), ".app"), name) {
+				matches = append(matches, app.Path)
+			}
+		}
+	}
+	matches = slices.Compact(matches)
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w: '%s' is not installed in %s", ErrNotFound, entry, strings.Join(r.Dirs, ", "))
+	case 1:
+		slog.Debug("resolved app", "entry", entry, "path", matches[0])
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%w: '%s' matches %s", ErrAmbiguous, entry, strings.Join(matches, ", "))
+	}
+}
+
+// BundleID returns the bundle identifier of the app at path.
+func (r *Resolver) BundleID(path string) (string, error) {
+	apps, err := r.index()
+	if err != nil {
+		return "", err
+	}
+	for _, app := range apps {
+		if app.Path == path {
+			return app.BundleID, nil
+		}
+	}
+
+	app, err := readApp(path)
+	if err != nil {
+		return "", err
+	}
+	return app.BundleID, nil
+}
+
+func (r *Resolver) index() ([]App, error) {
+	r.once.Do(func() {
+		for _, dir := range r.Dirs {
+			entries, err := os.ReadDir(dir)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				r.err = fmt.Errorf("failed to read application dir '%s': %w", dir, err)
+				return
+			}
+			for _, e := range entries {
+				if !strings.HasSuffix(e.Name(), ".app") {
+					continue
+				}
+				app, err := readApp(filepath.Join(dir, e.Name()))
+				if err != nil {
+					slog.Debug("skipping app", "path", filepath.Join(dir, e.Name()), "error", err)
+					continue
+				}
+				r.apps = append(r.apps, app)
+			}
+		}
+	})
+	return r.apps, r.err
+}
+
+func readApp(path string) (App, error) {
+	data, err := os.ReadFile(filepath.Join(path, "Contents", "Info.plist"))
+	if err != nil {
+		return App{}, fmt.Errorf("failed to read Info.plist of '%s': %w", path, err)
+	}
+
+	var i info
+	if _, err := plist.Unmarshal(data, &i); err != nil {
+		return App{}, fmt.Errorf("failed to parse Info.plist of '%s': %w", path, err)
+	}
+	if i.BundleID == "" {
+		return App{}, fmt.Errorf("'%s' has no bundle identifier", path)
+	}
+
+	name := i.DisplayName
+	if name == "" {
+		name = i.Name
+	}
+	return App{Path: path, BundleID: i.BundleID, Name: name}, nil
+}
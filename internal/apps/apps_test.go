@@ -0,0 +1,120 @@
+package apps
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"howett.net/plist"
+)
+
+func writeApp(t *testing.T, dir, name string, i info) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name+".app")
+	if err := os.MkdirAll(filepath.Join(path, "Contents"), 0755); err != nil {
+		t.Fatalf("failed to create app bundle: %v", err)
+	}
+	data, err := plist.Marshal(i, plist.XMLFormat)
+	if err != nil {
+		t.Fatalf("failed to marshal Info.plist: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "Contents", "Info.plist"), data, 0644); err != nil {
+		t.Fatalf("failed to write Info.plist: %v", err)
+	}
+	return path
+}
+
+func Test_Resolver(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	system := filepath.Join(tmp, "System", "Applications")
+	apps := filepath.Join(tmp, "Applications")
+	home := filepath.Join(tmp, "home")
+	userApps := filepath.Join(home, "Applications")
+
+	notes := writeApp(t, system, "Notes", info{BundleID: "com.apple.Notes", Name: "Notes"})
+	code := writeApp(t, apps, "Visual Studio Code", info{BundleID: "com.microsoft.VSCode", Name: "Code"})
+	writeApp(t, apps, "Chess", info{BundleID: "com.apple.Chess", Name: "Chess"})
+	writeApp(t, userApps, "Chess", info{BundleID: "com.example.chess", Name: "Chess"})
+	writeApp(t, apps, "Broken", info{})
+
+	r := &Resolver{Dirs: []string{apps, filepath.Join(home, "Applications"), system, filepath.Join(tmp, "missing")}}
+
+	tests := map[string]struct {
+		entry   string
+		want    string
+		wantErr error
+	}{
+		"bundle id":           {entry: "com.apple.Notes", want: notes},
+		"bundle id any case":  {entry: "com.apple.notes", want: notes},
+		"bundle name":         {entry: "Code", want: code},
+		"file name":           {entry: "Visual Studio Code", want: code},
+		"file name with .app": {entry: "Notes.app", want: notes},
+		"path unchanged":      {entry: "/Applications/Safari.app", want: "/Applications/Safari.app"},
+		"spacer unchanged":    {entry: " ", want: " "},
+		"ambiguous name":      {entry: "Chess", wantErr: ErrAmbiguous},
+		"unknown bundle id":   {entry: "com.example.missing", wantErr: ErrNotFound},
+		"unknown name":        {entry: "Missing", wantErr: ErrNotFound},
+		"no bundle id":        {entry: "Broken", wantErr: ErrNotFound},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := r.Resolve(tc.entry)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Resolve(%s) error = %v, want %v", tc.entry, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Fatalf("Resolve(%s) = %s, want %s", tc.entry, got, tc.want)
+			}
+		})
+	}
+
+	t.Run("bundle id of path", func(t *testing.T) {
+		t.Parallel()
+
+		if id, err := r.BundleID(code); err != nil || id != "com.microsoft.VSCode" {
+			t.Fatalf("BundleID(%s) = %s, %v", code, id, err)
+		}
+		if _, err := r.BundleID(filepath.Join(tmp, "Missing.app")); err == nil {
+			t.Fatalf("expected error for missing app")
+		}
+	})
+}
+
+func Test_IsBundleID(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		entry string
+		want  bool
+	}{
+		"bundle id": {entry: "com.apple.Notes", want: true},
+		"name":      {entry: "Notes", want: false},
+		"app name":  {entry: "Notes.app", want: false},
+		"path":      {entry: "/System/Applications/Notes.app", want: false},
+		"spaces":    {entry: "Visual Studio.Code", want: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsBundleID(tc.entry); got != tc.want {
+				t.Fatalf("IsBundleID(%s) = %v, want %v", tc.entry, got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_NewResolver(t *testing.T) {
+	t.Parallel()
+
+	r := NewResolver("/Users/test")
+	if len(r.Dirs) != len(DefaultDirs) || r.Dirs[2] != "/Users/test/Applications" {
+		t.Fatalf("unexpected dirs: %v", r.Dirs)
+	}
+}
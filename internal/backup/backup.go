@@ -0,0 +1,177 @@
+package backup
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Latest      = "latest"
+	DefaultKeep = 10
+
+	// PreRestore tags the snapshots taken by a restore. Latest skips them, so
+	// that restoring the latest backup twice does not undo the first restore.
+	PreRestore = "pre-restore"
+
+	idLayout = "20060102-150405.000000"
+	ext      = ".plist"
+)
+
+type Backup struct {
+	ID   string
+	Path string
+	Tag  string
+	Time time.Time
+	Size int64
+}
+
+// Store keeps timestamped Dock plist snapshots in Dir, pruning all but the
+// newest Keep of them. A Keep of zero or less keeps every snapshot.
+type Store struct {
+	Dir  string
+	Keep int
+}
+
+// DefaultDir returns $DORG_BACKUP_DIR, or the dorg backups directory under
+// the XDG state dir.
+func DefaultDir() (string, error) {
+	if dir := os.Getenv("DORG_BACKUP_DIR"); dir != "" {
+		return dir, nil
+	}
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "dorg", "backups"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %v", err)
+	}
+	return filepath.Join(home, ".local", "state", "dorg", "backups"), nil
+}
+
+// DefaultKeepCount returns $DORG_BACKUP_KEEP, or DefaultKeep when unset.
+func DefaultKeepCount() (int, error) {
+	v := os.Getenv("DORG_BACKUP_KEEP")
+	if v == "" {
+		return DefaultKeep, nil
+	}
+	keep, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid DORG_BACKUP_KEEP '%s': %v", v, err)
+	}
+	return keep, nil
+}
+
+func (s *Store) Create(data []byte) (Backup, error) {
+	return s.CreateTagged(data, "")
+}
+
+// CreateTagged is Create for a snapshot marked with tag, which becomes part
+// of its ID.
+func (s *Store) CreateTagged(data []byte, tag string) (Backup, error) {
+	if err := os.MkdirAll(s.Dir, 0750); err != nil {
+		return Backup{}, fmt.Errorf("failed to create backup dir: %w", err)
+	}
+
+	now := time.Now().UTC()
+	id := now.Format(idLayout)
+	if tag != "" {
+		id += "_" + tag
+	}
+	path := filepath.Join(s.Dir, id+ext)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return Backup{}, fmt.Errorf("failed to write backup: %w", err)
+	}
+	slog.Debug("created dock plist backup", "id", id, "path", path)
+
+	if err := s.Prune(); err != nil {
+		return Backup{}, err
+	}
+	return Backup{ID: id, Path: path, Tag: tag, Time: now, Size: int64(len(data))}, nil
+}
+
+// List returns the stored backups, newest first.
+func (s *Store) List() ([]Backup, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup dir: %w", err)
+	}
+
+	var backups []Backup
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ext)
+		if !ok || e.IsDir() {
+			continue
+		}
+		stamp, tag, _ := strings.Cut(id, "_")
+		t, err := time.Parse(idLayout, stamp)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat backup '%s': %w", id, err)
+		}
+		backups = append(backups, Backup{ID: id, Path: filepath.Join(s.Dir, e.Name()), Tag: tag, Time: t, Size: info.Size()})
+	}
+
+	slices.SortFunc(backups, func(a, b Backup) int {
+		return strings.Compare(b.ID, a.ID)
+	})
+	return backups, nil
+}
+
+// Get looks up a backup by ID, or the newest one not taken by a restore for
+// Latest.
+func (s *Store) Get(id string) (Backup, error) {
+	backups, err := s.List()
+	if err != nil {
+		return Backup{}, err
+	}
+	if len(backups) == 0 {
+		return Backup{}, fmt.Errorf("no backups found in %s", s.Dir)
+	}
+	for _, b := range backups {
+		if b.ID == id || id == Latest && b.Tag != PreRestore {
+			return b, nil
+		}
+	}
+	return Backup{}, fmt.Errorf("backup '%s' not found", id)
+}
+
+func (s *Store) Read(id string) (Backup, []byte, error) {
+	b, err := s.Get(id)
+	if err != nil {
+		return Backup{}, nil, err
+	}
+	data, err := os.ReadFile(b.Path)
+	if err != nil {
+		return Backup{}, nil, fmt.Errorf("failed to read backup '%s': %w", b.ID, err)
+	}
+	return b, data, nil
+}
+
+func (s *Store) Prune() error {
+	if s.Keep <= 0 {
+		return nil
+	}
+	backups, err := s.List()
+	if err != nil {
+		return err
+	}
+	for _, b := range backups[min(s.Keep, len(backups)):] {
+		slog.Debug("removing old dock plist backup", "id", b.ID)
+		if err := os.Remove(b.Path); err != nil {
+			return fmt.Errorf("failed to remove backup '%s': %w", b.ID, err)
+		}
+	}
+	return nil
+}
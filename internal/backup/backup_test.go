@@ -0,0 +1,137 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_Store(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		keep      int
+		creates   int
+		wantCount int
+	}{
+		"keep all":   {keep: 0, creates: 3, wantCount: 3},
+		"keep two":   {keep: 2, creates: 4, wantCount: 2},
+		"under keep": {keep: 5, creates: 1, wantCount: 1},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &Store{Dir: filepath.Join(t.TempDir(), "backups"), Keep: tc.keep}
+			var last Backup
+			for i := range tc.creates {
+				b, err := s.Create([]byte{byte(i)})
+				if err != nil {
+					t.Fatalf("Create error: %v", err)
+				}
+				last = b
+			}
+
+			backups, err := s.List()
+			if err != nil {
+				t.Fatalf("List error: %v", err)
+			}
+			if len(backups) != tc.wantCount {
+				t.Fatalf("got %d backups, want %d", len(backups), tc.wantCount)
+			}
+			if backups[0].ID != last.ID {
+				t.Fatalf("newest backup = %s, want %s", backups[0].ID, last.ID)
+			}
+
+			b, data, err := s.Read(Latest)
+			if err != nil {
+				t.Fatalf("Read(latest) error: %v", err)
+			}
+			if b.ID != last.ID || len(data) != 1 || data[0] != byte(tc.creates-1) {
+				t.Fatalf("Read(latest) = %s %v", b.ID, data)
+			}
+			if _, err := s.Get(backups[len(backups)-1].ID); err != nil {
+				t.Fatalf("Get(oldest) error: %v", err)
+			}
+		})
+	}
+}
+
+func Test_Store_Get(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "not-a-backup.txt"), nil, 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	tests := map[string]struct {
+		dir     string
+		id      string
+		wantErr bool
+	}{
+		"missing dir": {dir: filepath.Join(dir, "missing"), id: Latest, wantErr: true},
+		"no backups":  {dir: dir, id: Latest, wantErr: true},
+		"unknown id":  {dir: dir, id: "20200101-000000.000000", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &Store{Dir: tc.dir}
+			if _, err := s.Get(tc.id); (err != nil) != tc.wantErr {
+				t.Fatalf("Get(%s) error = %v, wantErr=%v", tc.id, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func Test_Store_PreRestore(t *testing.T) {
+	t.Parallel()
+
+	s := &Store{Dir: t.TempDir()}
+	want, err := s.Create([]byte("loaded"))
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	pre, err := s.CreateTagged([]byte("restored"), PreRestore)
+	if err != nil {
+		t.Fatalf("CreateTagged error: %v", err)
+	}
+
+	if got, err := s.Get(Latest); err != nil || got.ID != want.ID {
+		t.Fatalf("Get(latest) = %s, %v, want %s", got.ID, err, want.ID)
+	}
+	got, err := s.Get(pre.ID)
+	if err != nil {
+		t.Fatalf("Get(%s) error: %v", pre.ID, err)
+	}
+	if got.Tag != PreRestore {
+		t.Fatalf("Get(%s) tag = %q, want %s", pre.ID, got.Tag, PreRestore)
+	}
+}
+
+func Test_DefaultDir(t *testing.T) {
+	tests := map[string]struct {
+		env  map[string]string
+		want string
+	}{
+		"override":  {env: map[string]string{"DORG_BACKUP_DIR": "/tmp/b", "XDG_STATE_HOME": "/tmp/s"}, want: "/tmp/b"},
+		"xdg state": {env: map[string]string{"DORG_BACKUP_DIR": "", "XDG_STATE_HOME": "/tmp/s"}, want: "/tmp/s/dorg/backups"},
+		"home":      {env: map[string]string{"DORG_BACKUP_DIR": "", "XDG_STATE_HOME": "", "HOME": "/tmp/h"}, want: "/tmp/h/.local/state/dorg/backups"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			got, err := DefaultDir()
+			if err != nil {
+				t.Fatalf("DefaultDir error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("DefaultDir = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
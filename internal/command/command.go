@@ -1,28 +1,51 @@
 package command
 
 import (
-	"bytes"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
 
+	"github.com/5ouma/dorg/internal/backup"
 	"github.com/5ouma/dorg/internal/config"
 	"github.com/5ouma/dorg/internal/dock"
+	"github.com/5ouma/dorg/internal/remote"
+	"github.com/5ouma/dorg/internal/runner"
 	"github.com/5ouma/dorg/internal/utils"
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Cmd      string
-	File     string
-	LogLevel int
+	Cmd       string
+	File      string
+	LogLevel  int
+	Backup    *backup.Store
+	DryRun    bool
+	Output    string
+	Runner    runner.Runner
+	Target    Target
+	BundleIDs bool
+	SkipApps  bool
+	// ClearRecents empties the recent applications section on load when the
+	// config does not list any recents.
+	ClearRecents bool
+	// Seed changes the GUIDs given to new Dock tiles.
+	Seed int64
+	// Mode overrides the mode the config asks for.
+	Mode config.Mode
+	// Profile selects the profile of the config file to apply.
+	Profile string
+	// Read reads the config file, which may also be a URL or standard input.
+	Read config.Reader
+	// Format is the format of the config file, told by its extension when empty.
+	Format config.Format
 }
 
 func (c *Config) Verify() error {
-	if err := os.MkdirAll(filepath.Dir(c.File), 0750); err != nil {
-		return fmt.Errorf("failed to create config dir: %w", err)
+	if remote.IsLocal(c.File) {
+		if err := os.MkdirAll(filepath.Dir(c.File), 0750); err != nil {
+			return fmt.Errorf("failed to create config dir: %w", err)
+		}
 	}
 
 	slog.Debug(fmt.Sprintf("📄 Using config file: %s", c.File))
@@ -30,8 +53,19 @@ func (c *Config) Verify() error {
 	return nil
 }
 
+func (c *Config) runner() runner.Runner {
+	if c.Runner == nil {
+		return runner.Exec{}
+	}
+	return c.Runner
+}
+
 func SaveConfig(c *Config) (err error) {
-	dPlist, err := dock.LoadDockPlist()
+	if !remote.IsLocal(c.File) {
+		return errors.Errorf("unable to save to %s: the config file must be a local path", c.File)
+	}
+
+	dPlist, err := c.Target.load()
 	if err != nil {
 		return errors.Wrap(err, "unable to load dock plist")
 	}
@@ -41,23 +75,36 @@ func SaveConfig(c *Config) (err error) {
 		return errors.Wrap(err, "unable to generate config from dock plist")
 	}
 
+	if c.BundleIDs {
+		l, err := c.Target.Location()
+		if err != nil {
+			return err
+		}
+		useBundleIDs(&conf, l)
+	}
+
+	fmt.Println(utils.H2.Render("Apps"))
+	for _, app := range conf.Dock.Apps {
+		fmt.Println(utils.CheckedItem.Render(), app.String())
+	}
+	fmt.Println(utils.H2.Render("Folders"))
+	for _, other := range conf.Dock.Others {
+		fmt.Println(utils.CheckedItem.Render(), other.Key())
+	}
+	printRecents(conf.Dock.Recents)
+
 	if err := os.MkdirAll(filepath.Dir(c.File), 0750); err != nil {
 		return fmt.Errorf("failed to create config dir: %w", err)
 	}
 
-	buf := new(bytes.Buffer)
-	enc := yaml.NewEncoder(buf)
-	enc.SetIndent(2)
-	if err := enc.Encode(&conf); err != nil {
-		if err := enc.Close(); err != nil {
-			slog.Warn("failed to close encoder after encode error", "error", err)
-		}
-		return errors.Wrap(err, "unable to encode YAML for logging")
+	format := c.Format
+	if format == "" {
+		format = config.FormatOf(c.File)
 	}
-	if err := enc.Close(); err != nil {
-		return errors.Wrap(err, "unable to close encoder")
+	data, err := config.Marshal(format, &conf)
+	if err != nil {
+		return errors.Wrapf(err, "unable to encode %s", format)
 	}
-	data := buf.Bytes()
 	if err := os.WriteFile(c.File, data, 0644); err != nil {
 		return err
 	}
@@ -67,49 +114,90 @@ func SaveConfig(c *Config) (err error) {
 }
 
 func LoadConfig(c *Config) (err error) {
-	conf, err := config.Load(c.File)
+	plan, err := PlanConfig(c)
 	if err != nil {
-		return fmt.Errorf("failed to load config file: %v", err)
+		return err
 	}
 
-	if len(conf.Dock.Apps) == 0 && len(conf.Dock.Others) == 0 && conf.Dock.Settings == nil {
-		return errors.Errorf("no dock configuration found in config file")
+	if c.DryRun || c.Output != "" {
+		return plan.Print(c.Output)
+	}
+	if plan.Unchanged {
+		fmt.Println(utils.Msg.Render("Dock is up to date"))
+		return nil
 	}
 
-	dPlist, err := dock.LoadDockPlist()
-	if err != nil {
-		return errors.Wrap(err, "unable to load dock plist")
+	fmt.Println(utils.H2.Render("Apps"))
+	for _, app := range plan.Desired.Dock.Apps {
+		fmt.Println(utils.CheckedItem.Render(), app.String())
+	}
+	fmt.Println(utils.H2.Render("Folders"))
+	for _, other := range plan.Desired.Dock.Others {
+		fmt.Println(utils.CheckedItem.Render(), other.Key())
+	}
+	printRecents(plan.Desired.Dock.Recents)
+
+	if c.Backup != nil {
+		if _, err := CreateBackup(c); err != nil {
+			return err
+		}
 	}
 
-	if len(dPlist.PersistentApps) > 0 {
-		dPlist.PersistentApps = nil
+	return c.write(plan.Plist)
+}
+
+func printRecents(recents []string) {
+	if len(recents) == 0 {
+		return
 	}
-	fmt.Println(utils.H2.Render("Apps"))
-	for _, app := range conf.Dock.Apps {
+	fmt.Println(utils.H2.Render("Recents"))
+	for _, app := range recents {
 		fmt.Println(utils.CheckedItem.Render(), app)
-		dPlist.AddApp(app)
 	}
+}
 
-	if len(dPlist.PersistentOthers) > 0 {
-		dPlist.PersistentOthers = nil
+func CreateBackup(c *Config) (backup.Backup, error) {
+	return createBackup(c, "")
+}
+
+func createBackup(c *Config, tag string) (backup.Backup, error) {
+	l, err := c.Target.Location()
+	if err != nil {
+		return backup.Backup{}, err
 	}
-	fmt.Println(utils.H2.Render("Folders"))
-	for _, other := range conf.Dock.Others {
-		fmt.Println(utils.CheckedItem.Render(), other.Path)
-		if err := dPlist.AddOther(other); err != nil {
-			return errors.Wrapf(err, "unable to add other %s", other.Path)
-		}
+	data, err := os.ReadFile(l.PlistPath())
+	if err != nil {
+		return backup.Backup{}, fmt.Errorf("failed to read dock plist: %w", err)
 	}
 
-	if conf.Dock.Settings != nil {
-		if err := dPlist.ApplySettings(*conf.Dock.Settings); err != nil {
-			return fmt.Errorf("failed to apply dock settings: %w", err)
-		}
+	b, err := c.Backup.CreateTagged(data, tag)
+	if err != nil {
+		return backup.Backup{}, errors.Wrap(err, "unable to back up dock plist")
+	}
+	fmt.Println(utils.CheckedItem.Render(), "Backup", b.ID)
+	return b, nil
+}
+
+func RestoreBackup(c *Config, id string) error {
+	b, data, err := c.Backup.Read(id)
+	if err != nil {
+		return err
 	}
 
-	if err := dPlist.Save(); err != nil {
-		return fmt.Errorf("failed to save dock plist: %w", err)
+	dPlist, err := dock.ParsePlist(data)
+	if err != nil {
+		return errors.Wrapf(err, "unable to parse backup %s", b.ID)
+	}
+	l, err := c.Target.Location()
+	if err != nil {
+		return err
+	}
+	dPlist.SetLocation(l)
+
+	if _, err := createBackup(c, backup.PreRestore); err != nil {
+		return err
 	}
 
-	return utils.RestartDock()
+	fmt.Println(utils.CheckedItem.Render(), "Restore", b.ID)
+	return c.write(dPlist)
 }
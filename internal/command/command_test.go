@@ -3,7 +3,15 @@ package command
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/5ouma/dorg/internal/backup"
+	"github.com/5ouma/dorg/internal/config"
+	"github.com/5ouma/dorg/internal/dock"
+	"github.com/5ouma/dorg/internal/runner"
+	"howett.net/plist"
 )
 
 func Test_Verify(t *testing.T) {
@@ -13,9 +21,12 @@ func Test_Verify(t *testing.T) {
 
 	tests := map[string]struct {
 		cfg     *Config
+		wantDir bool
 		wantErr bool
 	}{
-		"create dir": {cfg: &Config{File: file}, wantErr: false},
+		"create dir": {cfg: &Config{File: file}, wantDir: true, wantErr: false},
+		"url":        {cfg: &Config{File: "https://example.com/dorg.yml"}, wantErr: false},
+		"stdin":      {cfg: &Config{File: "-"}, wantErr: false},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -24,9 +35,580 @@ func Test_Verify(t *testing.T) {
 			if err := tc.cfg.Verify(); (err != nil) != tc.wantErr {
 				t.Fatalf("err=%v, wantErr=%v", err, tc.wantErr)
 			}
-			if _, err := os.Stat(filepath.Dir(tc.cfg.File)); os.IsNotExist(err) {
+			if _, err := os.Stat(filepath.Dir(tc.cfg.File)); tc.wantDir && os.IsNotExist(err) {
 				t.Fatalf("expected dir created")
 			}
 		})
 	}
 }
+
+func Test_LoadSource(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	yml := filepath.Join(tmp, "dorg.yml")
+	if err := os.WriteFile(yml, []byte(`dock_items: {apps: ["/A.app"]}`), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	plistFile := filepath.Join(tmp, "backup.plist")
+	data, err := plist.Marshal(&dock.Plist{
+		PersistentApps: []dock.PAItem{{TileType: "file-tile", TileData: dock.TileData{FileData: dock.FileData{URLString: "file:///A.app/"}}}},
+	}, plist.BinaryFormat)
+	if err != nil {
+		t.Fatalf("failed to marshal plist: %v", err)
+	}
+	if err := os.WriteFile(plistFile, data, 0644); err != nil {
+		t.Fatalf("failed to write plist file: %v", err)
+	}
+
+	tests := map[string]struct {
+		src     string
+		wantErr bool
+	}{
+		"yaml file":    {src: yml, wantErr: false},
+		"plist file":   {src: plistFile, wantErr: false},
+		"missing file": {src: filepath.Join(tmp, "missing.yml"), wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			conf, err := LoadSource(tc.src, Target{}, config.LoadOptions{})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("LoadSource(%s) error = %v, wantErr=%v", tc.src, err, tc.wantErr)
+			}
+			if err == nil && (len(conf.Dock.Apps) != 1 || conf.Dock.Apps[0].Path != "/A.app") {
+				t.Fatalf("unexpected apps: %#v", conf.Dock.Apps)
+			}
+		})
+	}
+}
+
+func Test_CreateBackup(t *testing.T) {
+	tests := map[string]struct {
+		createPlist bool
+		wantErr     bool
+	}{
+		"existing plist": {createPlist: true, wantErr: false},
+		"missing plist":  {createPlist: false, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+
+			if tc.createPlist {
+				path := filepath.Join(home, "Library", "Preferences", "com.apple.dock.plist")
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf("failed to create prefs dir: %v", err)
+				}
+				if err := os.WriteFile(path, []byte("plist"), 0644); err != nil {
+					t.Fatalf("failed to write plist: %v", err)
+				}
+			}
+
+			store := &backup.Store{Dir: filepath.Join(home, "backups")}
+			b, err := CreateBackup(&Config{Backup: store})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("CreateBackup error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if err == nil {
+				data, err := os.ReadFile(b.Path)
+				if err != nil || string(data) != "plist" {
+					t.Fatalf("backup content = %q, err=%v", data, err)
+				}
+			}
+		})
+	}
+}
+
+func writeDockPlist(t *testing.T, p *dock.Plist) {
+	t.Helper()
+
+	l, err := dock.DefaultLocation()
+	if err != nil {
+		t.Fatalf("DefaultLocation error: %v", err)
+	}
+	path := l.PlistPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create prefs dir: %v", err)
+	}
+	data, err := plist.Marshal(p, plist.BinaryFormat)
+	if err != nil {
+		t.Fatalf("failed to marshal plist: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write plist: %v", err)
+	}
+}
+
+func Test_LoadConfig_DryRun(t *testing.T) {
+	tests := map[string]struct {
+		content     string
+		wantChanges int
+	}{
+		"changes":    {content: `dock_items: {apps: ["/A.app", "/B.app"]}`, wantChanges: 1},
+		"no changes": {content: `dock_items: {apps: ["/A.app"]}`, wantChanges: 0},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			writeDockPlist(t, &dock.Plist{
+				PersistentApps: []dock.PAItem{{TileType: "file-tile", TileData: dock.TileData{FileData: dock.FileData{URLString: "/A.app"}}}},
+			})
+
+			file := filepath.Join(home, "dorg.yml")
+			if err := os.WriteFile(file, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+
+			plan, err := PlanConfig(&Config{File: file, SkipApps: true})
+			if err != nil {
+				t.Fatalf("PlanConfig error: %v", err)
+			}
+			if len(plan.Changes.Changes) != tc.wantChanges {
+				t.Fatalf("got %d changes %+v, want %d", len(plan.Changes.Changes), plan.Changes.Changes, tc.wantChanges)
+			}
+
+			output := filepath.Join(home, "planned.plist")
+			if err := LoadConfig(&Config{File: file, DryRun: true, Output: output, SkipApps: true}); err != nil {
+				t.Fatalf("LoadConfig error: %v", err)
+			}
+			planned, err := dock.LoadPlistFile(output)
+			if err != nil {
+				t.Fatalf("failed to load planned plist: %v", err)
+			}
+			conf, err := planned.GenerateConfigFromPlist()
+			if err != nil {
+				t.Fatalf("GenerateConfigFromPlist error: %v", err)
+			}
+			if len(conf.Dock.Apps) != len(plan.Desired.Dock.Apps) {
+				t.Fatalf("planned apps = %v, want %v", conf.Dock.Apps, plan.Desired.Dock.Apps)
+			}
+		})
+	}
+}
+
+func Test_PlanConfig_Unchanged(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		want    bool
+	}{
+		"same tile size":     {content: `dock_items: {apps: ["/A.app"], settings: {tilesize: 48}}`, want: true},
+		"same delay":         {content: `dock_items: {apps: ["/A.app"], settings: {autohide-delay: 0.5}}`, want: true},
+		"another tile size":  {content: `dock_items: {apps: ["/A.app"], settings: {tilesize: 64}}`, want: false},
+		"settings not given": {content: `dock_items: {apps: ["/A.app"]}`, want: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			writeDockPlist(t, &dock.Plist{
+				PersistentApps: []dock.PAItem{{TileType: "file-tile", TileData: dock.TileData{FileData: dock.FileData{URLString: "/A.app"}}}},
+				TileSize:       48.0,
+				AutoHideDelay:  0.5,
+			})
+
+			file := filepath.Join(home, "dorg.yml")
+			if err := os.WriteFile(file, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+
+			plan, err := PlanConfig(&Config{File: file, SkipApps: true})
+			if err != nil {
+				t.Fatalf("PlanConfig error: %v", err)
+			}
+			if plan.Unchanged != tc.want {
+				t.Fatalf("unchanged = %v, want %v (changes %+v)", plan.Unchanged, tc.want, plan.Changes.Changes)
+			}
+		})
+	}
+}
+
+func Test_PlanConfig_Recents(t *testing.T) {
+	tests := map[string]struct {
+		content      string
+		clearRecents bool
+		want         []string
+	}{
+		"listed":         {content: `dock_items: {apps: ["/A.app"], recents: ["/C.app", "/D.app"]}`, want: []string{"/C.app", "/D.app"}},
+		"kept":           {content: `dock_items: {apps: ["/A.app"]}`, want: []string{"/B.app"}},
+		"cleared":        {content: `dock_items: {apps: ["/A.app"]}`, clearRecents: true, want: nil},
+		"listed wins":    {content: `dock_items: {recents: ["/C.app"]}`, clearRecents: true, want: []string{"/C.app"}},
+		"listed as none": {content: `dock_items: {apps: ["/A.app"], recents: []}`, want: nil},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			writeDockPlist(t, &dock.Plist{
+				PersistentApps: []dock.PAItem{{TileType: "file-tile", TileData: dock.TileData{FileData: dock.FileData{URLString: "/A.app"}}}},
+				RecentApps:     []dock.PAItem{{TileType: "file-tile", TileData: dock.TileData{FileData: dock.FileData{URLString: "/B.app"}}}},
+			})
+
+			file := filepath.Join(home, "dorg.yml")
+			if err := os.WriteFile(file, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+
+			plan, err := PlanConfig(&Config{File: file, SkipApps: true, ClearRecents: tc.clearRecents})
+			if err != nil {
+				t.Fatalf("PlanConfig error: %v", err)
+			}
+			if !slices.Equal(plan.Desired.Dock.Recents, tc.want) {
+				t.Fatalf("recents = %v, want %v", plan.Desired.Dock.Recents, tc.want)
+			}
+		})
+	}
+}
+
+func Test_PlanConfig_Ensure(t *testing.T) {
+	tests := map[string]struct {
+		content    string
+		mode       config.Mode
+		want       []string
+		wantOthers []string
+		wantErr    bool
+	}{
+		"config mode": {
+			content: "mode: ensure\ndock_items: {present: [{app: /C.app, after: /A.app}], absent: [/B.app]}",
+			want:    []string{"/A.app", "/C.app"},
+		},
+		"flag mode": {
+			content: "dock_items: {present: [{app: /C.app, position: first}]}",
+			mode:    config.ModeEnsure,
+			want:    []string{"/C.app", "/A.app", "/B.app"},
+		},
+		"exact mode": {content: "mode: exact\ndock_items: {present: [/C.app]}", wantErr: true},
+		"existing folder kept": {
+			content:    "mode: ensure\ndock_items: {present: [/C.app]}",
+			want:       []string{"/A.app", "/B.app", "/C.app"},
+			wantOthers: []string{"/Users/Shared"},
+		},
+		"listed others managed": {
+			content:    "mode: ensure\ndock_items: {present: [/C.app], others: [{path: /Applications}]}",
+			want:       []string{"/A.app", "/B.app", "/C.app"},
+			wantOthers: []string{"/Applications"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			writeDockPlist(t, &dock.Plist{
+				PersistentApps: []dock.PAItem{
+					{GUID: 1, TileType: "file-tile", TileData: dock.TileData{FileData: dock.FileData{URLString: "file:///A.app/", URLStringType: 15}}},
+					{GUID: 2, TileType: "file-tile", TileData: dock.TileData{FileData: dock.FileData{URLString: "file:///B.app/", URLStringType: 15}}},
+				},
+				PersistentOthers: []dock.POItem{
+					{GUID: 3, TileType: "directory-tile", TileData: dock.POTileData{FileData: dock.FileData{URLString: "file:///Users/Shared/", URLStringType: 15}}},
+				},
+			})
+
+			file := filepath.Join(home, "dorg.yml")
+			if err := os.WriteFile(file, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+
+			plan, err := PlanConfig(&Config{File: file, SkipApps: true, Mode: tc.mode})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("PlanConfig error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var got []string
+			for _, app := range plan.Desired.Dock.Apps {
+				got = append(got, app.String())
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("apps = %v, want %v", got, tc.want)
+			}
+			if plan.Plist.PersistentApps[slices.Index(got, "/A.app")].GUID != 1 {
+				t.Fatalf("kept app lost its GUID: %+v", plan.Plist.PersistentApps)
+			}
+			if tc.wantOthers != nil {
+				var others []string
+				for _, other := range plan.Plist.PersistentOthers {
+					others = append(others, other.TileData.GetPath())
+				}
+				if !slices.Equal(others, tc.wantOthers) {
+					t.Fatalf("others = %v, want %v", others, tc.wantOthers)
+				}
+			}
+		})
+	}
+}
+
+func Test_Target(t *testing.T) {
+	tests := map[string]struct {
+		env       map[string]string
+		conf      *config.Target
+		want      Target
+		wantPlist string
+		wantErr   bool
+	}{
+		"defaults": {
+			env:       map[string]string{"DORG_HOME": "", "DORG_PLIST": "", "DORG_OFFLINE": ""},
+			want:      Target{},
+			wantPlist: "/home/dorg/Library/Preferences/com.apple.dock.plist",
+		},
+		"env": {
+			env:       map[string]string{"DORG_HOME": "/Users/template", "DORG_PLIST": "", "DORG_OFFLINE": "true"},
+			want:      Target{Home: "/Users/template", Offline: true},
+			wantPlist: "/Users/template/Library/Preferences/com.apple.dock.plist",
+		},
+		"config fills unset": {
+			env:       map[string]string{"DORG_HOME": "/Users/template", "DORG_PLIST": "", "DORG_OFFLINE": ""},
+			conf:      &config.Target{Home: "/Users/other", Plist: "/tmp/dock.plist", Offline: true},
+			want:      Target{Home: "/Users/template", Plist: "/tmp/dock.plist", Offline: true},
+			wantPlist: "/tmp/dock.plist",
+		},
+		"invalid offline": {
+			env:     map[string]string{"DORG_HOME": "", "DORG_PLIST": "", "DORG_OFFLINE": "maybe"},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("HOME", "/home/dorg")
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			got, err := TargetFromEnv()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("TargetFromEnv error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got = got.WithConfig(tc.conf)
+			if got != tc.want {
+				t.Fatalf("target = %+v, want %+v", got, tc.want)
+			}
+			l, err := got.Location()
+			if err != nil {
+				t.Fatalf("Location error: %v", err)
+			}
+			if l.PlistPath() != tc.wantPlist {
+				t.Fatalf("plist path = %s, want %s", l.PlistPath(), tc.wantPlist)
+			}
+		})
+	}
+}
+
+func Test_RestoreBackup_Twice(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeDockPlist(t, &dock.Plist{Orientation: ptr("left")})
+
+	c := &Config{Backup: &backup.Store{Dir: filepath.Join(home, "backups")}, Target: Target{Offline: true}}
+	if _, err := CreateBackup(c); err != nil {
+		t.Fatalf("CreateBackup error: %v", err)
+	}
+	writeDockPlist(t, &dock.Plist{Orientation: ptr("right")})
+
+	for range 2 {
+		if err := RestoreBackup(c, backup.Latest); err != nil {
+			t.Fatalf("RestoreBackup error: %v", err)
+		}
+		got, err := Target{}.load()
+		if err != nil {
+			t.Fatalf("failed to load restored plist: %v", err)
+		}
+		if got.Orientation == nil || *got.Orientation != "left" {
+			t.Fatalf("orientation = %v, want left", got.Orientation)
+		}
+	}
+}
+
+func Test_LoadConfig_Offline(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	target := t.TempDir()
+	plistPath := filepath.Join(target, "Library", "Preferences", "com.apple.dock.plist")
+	if err := os.MkdirAll(filepath.Dir(plistPath), 0755); err != nil {
+		t.Fatalf("failed to create prefs dir: %v", err)
+	}
+	data, err := plist.Marshal(map[string]any{"orientation": "left"}, plist.BinaryFormat)
+	if err != nil {
+		t.Fatalf("failed to marshal plist: %v", err)
+	}
+	if err := os.WriteFile(plistPath, data, 0644); err != nil {
+		t.Fatalf("failed to write plist: %v", err)
+	}
+
+	file := filepath.Join(target, "dorg.yml")
+	content := "dock_items: {apps: [/A.app], others: [{path: ~/Documents}]}\ntarget: {offline: true}\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	rec := &runner.Recorder{}
+	if err := LoadConfig(&Config{File: file, Runner: rec, Target: Target{Home: target}, SkipApps: true}); err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	if cmds := rec.Commands(); len(cmds) != 0 {
+		t.Fatalf("offline load ran commands: %v", cmds)
+	}
+
+	got, err := dock.Load(dock.Location{Home: target})
+	if err != nil {
+		t.Fatalf("failed to load written plist: %v", err)
+	}
+	if len(got.PersistentApps) != 1 || got.PersistentOthers[0].TileData.GetPath() != filepath.Join(target, "Documents") {
+		t.Fatalf("unexpected written plist: %+v", got)
+	}
+}
+
+func Test_PlanConfig_TemplateHome(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	target := t.TempDir()
+	plistPath := filepath.Join(target, "com.apple.dock.plist")
+	data, err := plist.Marshal(map[string]any{}, plist.BinaryFormat)
+	if err != nil {
+		t.Fatalf("failed to marshal plist: %v", err)
+	}
+	if err := os.WriteFile(plistPath, data, 0644); err != nil {
+		t.Fatalf("failed to write plist: %v", err)
+	}
+
+	file := filepath.Join(target, "dorg.yml.tmpl")
+	if err := os.WriteFile(file, []byte("dock_items: {others: [{path: \"{{ .Home }}/Documents\"}]}\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	plan, err := PlanConfig(&Config{File: file, Target: Target{Home: target, Plist: plistPath}, SkipApps: true})
+	if err != nil {
+		t.Fatalf("PlanConfig error: %v", err)
+	}
+	if got := plan.Plist.PersistentOthers[0].TileData.GetPath(); got != filepath.Join(target, "Documents") {
+		t.Fatalf("others path = %s, want %s", got, filepath.Join(target, "Documents"))
+	}
+}
+
+func Test_LoadConfig_OtherDock(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	target := t.TempDir()
+	plistPath := filepath.Join(target, "com.apple.dock.plist")
+	data, err := plist.Marshal(map[string]any{"orientation": "left"}, plist.BinaryFormat)
+	if err != nil {
+		t.Fatalf("failed to marshal plist: %v", err)
+	}
+	if err := os.WriteFile(plistPath, data, 0644); err != nil {
+		t.Fatalf("failed to write plist: %v", err)
+	}
+
+	file := filepath.Join(target, "dorg.yml")
+	if err := os.WriteFile(file, []byte("dock_items: {apps: [/A.app]}\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	rec := &runner.Recorder{}
+	if err := LoadConfig(&Config{File: file, Runner: rec, Target: Target{Home: target, Plist: plistPath}, SkipApps: true}); err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	cmds := rec.Commands()
+	want := "/usr/bin/defaults import " + strings.TrimSuffix(plistPath, ".plist")
+	if len(cmds) != 1 || !strings.HasPrefix(cmds[0], want) {
+		t.Fatalf("commands = %v, want only %s", cmds, want)
+	}
+}
+
+func Test_LoadConfig_Unchanged(t *testing.T) {
+	tests := map[string]struct {
+		content  string
+		wantRuns bool
+	}{
+		"changes":    {content: `dock_items: {apps: ["/B.app", "/A.app"]}`, wantRuns: true},
+		"no changes": {content: `dock_items: {apps: ["/A.app", "/B.app"]}`, wantRuns: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			writeDockPlist(t, &dock.Plist{
+				PersistentApps: []dock.PAItem{
+					{GUID: 1, TileType: "file-tile", TileData: dock.TileData{FileData: dock.FileData{URLString: "file:///A.app/", URLStringType: 15}}},
+					{GUID: 2, TileType: "file-tile", TileData: dock.TileData{FileData: dock.FileData{URLString: "file:///B.app/", URLStringType: 15}}},
+				},
+			})
+
+			file := filepath.Join(home, "dorg.yml")
+			if err := os.WriteFile(file, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+
+			rec := &runner.Recorder{}
+			if err := LoadConfig(&Config{File: file, Runner: rec, SkipApps: true}); err != nil {
+				t.Fatalf("LoadConfig error: %v", err)
+			}
+			if ran := len(rec.Commands()) > 0; ran != tc.wantRuns {
+				t.Fatalf("ran commands = %v, want %v: %v", ran, tc.wantRuns, rec.Commands())
+			}
+		})
+	}
+}
+
+func Test_resolveApps(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	app := filepath.Join(home, "Applications", "Notes.app")
+	if err := os.MkdirAll(filepath.Join(app, "Contents"), 0755); err != nil {
+		t.Fatalf("failed to create app: %v", err)
+	}
+	data, err := plist.Marshal(map[string]string{"CFBundleIdentifier": "com.example.Notes"}, plist.XMLFormat)
+	if err != nil {
+		t.Fatalf("failed to marshal Info.plist: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(app, "Contents", "Info.plist"), data, 0644); err != nil {
+		t.Fatalf("failed to write Info.plist: %v", err)
+	}
+
+	small := config.App{Spacer: config.SpacerSmall}
+	tests := map[string]struct {
+		apps    []config.App
+		strict  bool
+		want    []config.App
+		wantErr bool
+	}{
+		"bundle id and name": {apps: []config.App{{Path: "com.example.Notes"}, {Path: "Notes"}, small, {Path: "/A.app"}}, strict: true, want: []config.App{{Path: app}, {Path: app}, small, {Path: "/A.app"}}},
+		"expanded paths":     {apps: []config.App{{Path: "~/Applications/Notes.app"}, {Path: "$HOME/Applications/Notes.app"}}, strict: true, want: []config.App{{Path: app}, {Path: app}}},
+		"unresolved strict":  {apps: []config.App{{Path: "com.example.Missing"}}, strict: true, wantErr: true},
+		"unresolved lenient": {apps: []config.App{{Path: "com.example.Missing"}}, strict: false, want: []config.App{{Path: "com.example.Missing"}}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			conf := config.Config{Dock: config.Dock{Apps: slices.Clone(tc.apps)}}
+			err := resolveApps(&conf, dock.Location{Home: home}, tc.strict)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("resolveApps error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if err == nil && !slices.Equal(conf.Dock.Apps, tc.want) {
+				t.Fatalf("apps = %v, want %v", conf.Dock.Apps, tc.want)
+			}
+		})
+	}
+
+	t.Run("bundle ids", func(t *testing.T) {
+		t.Parallel()
+
+		conf := config.Config{Dock: config.Dock{Apps: []config.App{{Path: app}, {Path: "/Missing.app"}, small}, Recents: []string{app}}}
+		useBundleIDs(&conf, dock.Location{Home: home})
+		want := []config.App{{Path: "com.example.Notes"}, {Path: "/Missing.app"}, small}
+		if !slices.Equal(conf.Dock.Apps, want) || conf.Dock.Recents[0] != "com.example.Notes" {
+			t.Fatalf("apps = %v, recents = %v", conf.Dock.Apps, conf.Dock.Recents)
+		}
+	})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
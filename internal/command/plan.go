@@ -0,0 +1,154 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/5ouma/dorg/internal/config"
+	"github.com/5ouma/dorg/internal/diff"
+	"github.com/5ouma/dorg/internal/dock"
+	"github.com/5ouma/dorg/internal/utils"
+	"github.com/pkg/errors"
+)
+
+// Plan is the Dock plist a load would write, along with how it differs from
+// the current one.
+type Plan struct {
+	Plist   *dock.Plist
+	Current config.Config
+	Desired config.Config
+	Changes diff.Result
+	// Unchanged is set when the planned plist is identical to the current one,
+	// so there is nothing to write.
+	Unchanged bool
+}
+
+// PlanConfig computes the Dock plist described by the config file without
+// touching the running Dock.
+func PlanConfig(c *Config) (*Plan, error) {
+	l, err := c.Target.Location()
+	if err != nil {
+		return nil, err
+	}
+	conf, err := config.Load(c.File, config.LoadOptions{Profile: c.Profile, Read: c.Read, Format: c.Format, Home: l.Home})
+	if err != nil {
+		if problems, verr := config.Validate(c.File, config.ValidateOptions{Home: l.Home, SkipApps: true, Read: c.Read, Format: c.Format}); verr == nil && len(problems) > 0 {
+			return nil, problems
+		}
+		return nil, fmt.Errorf("failed to load config file: %v", err)
+	}
+
+	if len(conf.Dock.Apps) == 0 && len(conf.Dock.Others) == 0 && conf.Dock.Recents == nil && conf.Dock.Settings == nil && len(conf.HotCorners) == 0 &&
+		len(conf.Dock.Present) == 0 && len(conf.Dock.Absent) == 0 {
+		return nil, errors.Errorf("no dock configuration found in config file")
+	}
+	mode, err := conf.ModeFor(c.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Target = c.Target.WithConfig(conf.Target)
+	l, err = c.Target.Location()
+	if err != nil {
+		return nil, err
+	}
+	problems, err := config.Validate(c.File, config.ValidateOptions{Home: l.Home, SkipApps: c.SkipApps, Read: c.Read, Format: c.Format})
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate config file: %v", err)
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	if err := resolveApps(&conf, l, true); err != nil {
+		return nil, err
+	}
+
+	dPlist, err := dock.Load(l)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load dock plist")
+	}
+
+	current, err := dPlist.GenerateConfigFromPlist()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to generate config from dock plist")
+	}
+
+	before, err := dPlist.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	dPlist.SetSeed(c.Seed)
+	if mode == config.ModeEnsure {
+		dPlist.ReconcileApps(conf.Dock.Ensure(current.Dock.Apps))
+	} else {
+		dPlist.ReconcileApps(conf.Dock.Apps)
+	}
+	// Ensure mode only manages the others section when the config lists some.
+	if mode == config.ModeExact || len(conf.Dock.Others) > 0 {
+		if err := dPlist.ReconcileOthers(conf.Dock.Others); err != nil {
+			return nil, errors.Wrap(err, "unable to add others")
+		}
+	}
+
+	switch {
+	case conf.Dock.Recents != nil:
+		dPlist.ReconcileRecents(conf.Dock.Recents)
+	case c.ClearRecents:
+		dPlist.ClearRecents()
+	}
+
+	if conf.Dock.Settings != nil {
+		if err := dPlist.ApplySettings(*conf.Dock.Settings); err != nil {
+			return nil, fmt.Errorf("failed to apply dock settings: %w", err)
+		}
+	}
+
+	if err := dPlist.ApplyHotCorners(conf.HotCorners); err != nil {
+		return nil, fmt.Errorf("failed to apply hot corners: %w", err)
+	}
+
+	desired, err := dPlist.GenerateConfigFromPlist()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to generate config from dock plist")
+	}
+
+	after, err := dPlist.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Plan{
+		Plist:     dPlist,
+		Current:   current,
+		Desired:   desired,
+		Changes:   diff.Compare(current, desired),
+		Unchanged: bytes.Equal(before, after),
+	}, nil
+}
+
+// Print shows the planned changes and, if output is set, writes the planned
+// plist there instead of applying it.
+func (p *Plan) Print(output string) error {
+	fmt.Println(utils.H2.Render("Planned changes"))
+	if p.Changes.Empty() {
+		fmt.Println(utils.Msg.Render("No changes"))
+	} else {
+		diff.Render(os.Stdout, p.Changes)
+	}
+
+	if output == "" {
+		return nil
+	}
+
+	data, err := p.Plist.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write planned dock plist: %w", err)
+	}
+	fmt.Println(utils.Msg.Render("✅", output))
+	return nil
+}
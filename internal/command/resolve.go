@@ -0,0 +1,88 @@
+package command
+
+import (
+	"log/slog"
+
+	"github.com/5ouma/dorg/internal/apps"
+	"github.com/5ouma/dorg/internal/config"
+	"github.com/5ouma/dorg/internal/dock"
+	"github.com/pkg/errors"
+)
+
+// resolveApps replaces bundle IDs and app names in conf with the paths of the
+// installed apps, and expands "~" and environment variables in app paths.
+// Unless strict, entries that cannot be resolved are kept as they are.
+func resolveApps(conf *config.Config, l dock.Location, strict bool) error {
+	resolver := apps.NewResolver(l.Home)
+	if err := resolveEntries(resolver, appEntries(conf), l, strict); err != nil {
+		return err
+	}
+	// Absent apps and position hints may name apps that are not installed.
+	return resolveEntries(resolver, hintEntries(conf), l, false)
+}
+
+func resolveEntries(resolver *apps.Resolver, entries []*string, l dock.Location, strict bool) error {
+	for _, app := range entries {
+		if apps.IsPath(*app) {
+			*app = config.ExpandPath(*app, l.Home)
+			continue
+		}
+		path, err := resolver.Resolve(*app)
+		if err != nil {
+			if strict {
+				return errors.Wrapf(err, "unable to resolve app")
+			}
+			slog.Debug("keeping unresolved app", "app", *app, "error", err)
+			continue
+		}
+		*app = path
+	}
+	return nil
+}
+
+// appEntries points at every app path of conf, spacers excluded.
+func appEntries(conf *config.Config) []*string {
+	var out []*string
+	for i := range conf.Dock.Apps {
+		if !conf.Dock.Apps[i].IsSpacer() {
+			out = append(out, &conf.Dock.Apps[i].Path)
+		}
+	}
+	for i := range conf.Dock.Recents {
+		out = append(out, &conf.Dock.Recents[i])
+	}
+	for i := range conf.Dock.Present {
+		out = append(out, &conf.Dock.Present[i].App)
+	}
+	return out
+}
+
+// hintEntries points at the apps of conf that only refer to Dock tiles.
+func hintEntries(conf *config.Config) []*string {
+	var out []*string
+	for i := range conf.Dock.Present {
+		if conf.Dock.Present[i].After != "" {
+			out = append(out, &conf.Dock.Present[i].After)
+		}
+	}
+	for i := range conf.Dock.Absent {
+		out = append(out, &conf.Dock.Absent[i])
+	}
+	return out
+}
+
+// useBundleIDs replaces app paths in conf with their bundle IDs where they have one.
+func useBundleIDs(conf *config.Config, l dock.Location) {
+	resolver := apps.NewResolver(l.Home)
+	for _, app := range appEntries(conf) {
+		if !apps.IsPath(*app) {
+			continue
+		}
+		id, err := resolver.BundleID(*app)
+		if err != nil {
+			slog.Debug("keeping app path", "app", *app, "error", err)
+			continue
+		}
+		*app = id
+	}
+}
@@ -0,0 +1,69 @@
+package command
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/5ouma/dorg/internal/backup"
+	"github.com/5ouma/dorg/internal/config"
+	"github.com/5ouma/dorg/internal/dock"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SourceDock names the live Dock preferences as a diff source.
+	SourceDock = "dock"
+	// SourceBackupPrefix selects a stored backup, as in "backup:latest".
+	SourceBackupPrefix = "backup:"
+)
+
+// LoadSource reads a Dock configuration from the live Dock, a stored backup, a
+// saved plist or a config file. opts only applies to config files.
+func LoadSource(src string, t Target, opts config.LoadOptions) (config.Config, error) {
+	if id, ok := strings.CutPrefix(src, SourceBackupPrefix); ok {
+		dir, err := backup.DefaultDir()
+		if err != nil {
+			return config.Config{}, err
+		}
+		store := &backup.Store{Dir: dir}
+		b, err := store.Get(id)
+		if err != nil {
+			return config.Config{}, err
+		}
+		src = b.Path
+	}
+
+	if src == SourceDock || strings.EqualFold(filepath.Ext(src), ".plist") {
+		var (
+			dPlist *dock.Plist
+			err    error
+		)
+		if src == SourceDock {
+			dPlist, err = t.load()
+		} else {
+			dPlist, err = dock.LoadPlistFile(src)
+		}
+		if err != nil {
+			return config.Config{}, errors.Wrapf(err, "unable to load %s", src)
+		}
+		l, err := t.Location()
+		if err != nil {
+			return config.Config{}, err
+		}
+		dPlist.SetLocation(l)
+		return dPlist.GenerateConfigFromPlist()
+	}
+
+	conf, err := config.Load(src, opts)
+	if err != nil {
+		return config.Config{}, errors.Wrapf(err, "unable to load %s", src)
+	}
+	l, err := t.WithConfig(conf.Target).Location()
+	if err != nil {
+		return config.Config{}, err
+	}
+	if err := resolveApps(&conf, l, false); err != nil {
+		return config.Config{}, err
+	}
+	return conf, nil
+}
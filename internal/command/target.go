@@ -0,0 +1,102 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/5ouma/dorg/internal/config"
+	"github.com/5ouma/dorg/internal/dock"
+	"github.com/5ouma/dorg/internal/utils"
+)
+
+// Target selects the Dock plist and home directory a command reads and
+// writes. Offline targets are edited in place instead of through defaults
+// and launchctl.
+type Target struct {
+	Home    string
+	Plist   string
+	Offline bool
+}
+
+// TargetFromEnv reads $DORG_HOME, $DORG_PLIST and $DORG_OFFLINE.
+func TargetFromEnv() (Target, error) {
+	t := Target{
+		Home:  os.Getenv("DORG_HOME"),
+		Plist: os.Getenv("DORG_PLIST"),
+	}
+	if v := os.Getenv("DORG_OFFLINE"); v != "" {
+		offline, err := strconv.ParseBool(v)
+		if err != nil {
+			return Target{}, fmt.Errorf("invalid DORG_OFFLINE '%s': %v", v, err)
+		}
+		t.Offline = offline
+	}
+	return t, nil
+}
+
+// WithConfig fills in whatever t leaves unset from the config file's target section.
+func (t Target) WithConfig(conf *config.Target) Target {
+	if conf == nil {
+		return t
+	}
+	if t.Home == "" {
+		t.Home = conf.Home
+	}
+	if t.Plist == "" {
+		t.Plist = conf.Plist
+	}
+	t.Offline = t.Offline || conf.Offline
+	return t
+}
+
+func (t Target) Location() (dock.Location, error) {
+	l, err := dock.DefaultLocation()
+	if err != nil {
+		return dock.Location{}, err
+	}
+
+	if t.Home != "" {
+		if l.Home, err = filepath.Abs(t.Home); err != nil {
+			return dock.Location{}, fmt.Errorf("failed to get absolute path for '%s': %v", t.Home, err)
+		}
+	}
+	if t.Plist != "" {
+		if l.Plist, err = filepath.Abs(t.Plist); err != nil {
+			return dock.Location{}, fmt.Errorf("failed to get absolute path for '%s': %v", t.Plist, err)
+		}
+	}
+	return l, nil
+}
+
+func (t Target) load() (*dock.Plist, error) {
+	l, err := t.Location()
+	if err != nil {
+		return nil, err
+	}
+	return dock.Load(l)
+}
+
+// write stores p at the target and, unless the target is offline or another
+// user's Dock, restarts the Dock.
+func (c *Config) write(p *dock.Plist) error {
+	if c.Target.Offline {
+		if err := p.WriteFile(); err != nil {
+			return fmt.Errorf("failed to write dock plist: %w", err)
+		}
+		return nil
+	}
+
+	if err := p.Save(c.runner()); err != nil {
+		return fmt.Errorf("failed to save dock plist: %w", err)
+	}
+	l, err := c.Target.Location()
+	if err != nil {
+		return err
+	}
+	if !l.Current() {
+		return nil
+	}
+	return utils.RestartDock(c.runner())
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Spacer is the size of a fixed spacer tile.
+type Spacer string
+
+const (
+	SpacerSmall Spacer = "small"
+	SpacerLarge Spacer = "large"
+)
+
+var Spacers = []string{string(SpacerSmall), string(SpacerLarge)}
+
+// App is an item of the apps section: either the path of an app or a spacer.
+// In YAML an app is a plain string and a spacer a mapping such as
+// {spacer: small} or {flex-spacer: true}.
+type App struct {
+	Path       string `yaml:"-"`
+	Spacer     Spacer `yaml:"spacer,omitempty"`
+	FlexSpacer bool   `yaml:"flex-spacer,omitempty"`
+}
+
+func (a App) IsSpacer() bool {
+	return a.Spacer != "" || a.FlexSpacer
+}
+
+func (a App) String() string {
+	return spacerString(a.Path, a.Spacer, a.FlexSpacer)
+}
+
+func spacerString(key string, spacer Spacer, flex bool) string {
+	switch {
+	case flex:
+		return "flex-spacer"
+	case spacer != "":
+		return fmt.Sprintf("spacer: %s", spacer)
+	}
+	return key
+}
+
+type appSpacer App
+
+func (a App) MarshalYAML() (any, error) {
+	if !a.IsSpacer() {
+		return a.Path, nil
+	}
+	return appSpacer(a), nil
+}
+
+// UnmarshalYAML also accepts the legacy spacer encoding, where "" is a small
+// spacer and " " a large one.
+func (a *App) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.MappingNode {
+		var s appSpacer
+		if err := n.Decode(&s); err != nil {
+			return err
+		}
+		*a = App(s)
+		return nil
+	}
+
+	var path string
+	if err := n.Decode(&path); err != nil {
+		return err
+	}
+	switch path {
+	case "":
+		*a = App{Spacer: SpacerSmall}
+	case " ":
+		*a = App{Spacer: SpacerLarge}
+	default:
+		*a = App{Path: path}
+	}
+	return nil
+}
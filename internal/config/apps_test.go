@@ -0,0 +1,63 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func Test_App_Unmarshal(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content string
+		want    []App
+		wantErr bool
+	}{
+		"paths":          {content: "[/Applications/Safari.app, com.apple.Notes]", want: []App{{Path: "/Applications/Safari.app"}, {Path: "com.apple.Notes"}}},
+		"spacers":        {content: "[{spacer: small}, {spacer: large}, {flex-spacer: true}]", want: []App{{Spacer: SpacerSmall}, {Spacer: SpacerLarge}, {FlexSpacer: true}}},
+		"legacy spacers": {content: `["", " "]`, want: []App{{Spacer: SpacerSmall}, {Spacer: SpacerLarge}}},
+		"list":           {content: "[[/A.app]]", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []App
+			err := yaml.Unmarshal([]byte(tc.content), &got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Unmarshal error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Unmarshal = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_App_Marshal(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		apps []App
+		want string
+	}{
+		"path":         {apps: []App{{Path: "/Applications/Safari.app"}}, want: "- /Applications/Safari.app\n"},
+		"small spacer": {apps: []App{{Spacer: SpacerSmall}}, want: "- spacer: small\n"},
+		"flex spacer":  {apps: []App{{FlexSpacer: true}}, want: "- flex-spacer: true\n"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := yaml.Marshal(tc.apps)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			if string(data) != tc.want {
+				t.Fatalf("Marshal = %q, want %q", data, tc.want)
+			}
+		})
+	}
+}
@@ -1,26 +1,56 @@
 package config
 
 import (
-	"os"
-
-	yaml "gopkg.in/yaml.v3"
+	"reflect"
 )
 
 type Config struct {
-	Dock Dock `yaml:"dock_items"`
+	Mode       Mode       `yaml:"mode,omitempty"`
+	Dock       Dock       `yaml:"dock_items"`
+	HotCorners HotCorners `yaml:"hot_corners,omitempty"`
+	Target     *Target    `yaml:"target,omitempty"`
+}
+
+type Target struct {
+	Home    string `yaml:"home,omitempty"`
+	Plist   string `yaml:"plist,omitempty"`
+	Offline bool   `yaml:"offline,omitempty"`
 }
 
 type Dock struct {
-	Apps     []string      `yaml:"apps,omitempty"`
+	Apps     []App         `yaml:"apps,omitempty"`
 	Others   []Folder      `yaml:"others,omitempty"`
+	Recents  []string      `yaml:"recents,omitempty"`
 	Settings *DockSettings `yaml:"settings,omitempty"`
+
+	// Present and Absent replace Apps in ensure mode.
+	Present []EnsureApp `yaml:"present,omitempty"`
+	Absent  []string    `yaml:"absent,omitempty"`
 }
 
+// Folder is an item of the others section: a folder (Path), a single
+// document (File), a link (URL) or a spacer.
 type Folder struct {
-	Path    string `yaml:"path,omitempty"`
-	Sort    int    `yaml:"sort,omitempty"`
-	Display int    `yaml:"display,omitempty"`
-	View    int    `yaml:"view,omitempty"`
+	Path       string  `yaml:"path,omitempty"`
+	File       string  `yaml:"file,omitempty"`
+	URL        string  `yaml:"url,omitempty"`
+	Label      string  `yaml:"label,omitempty"`
+	Spacer     Spacer  `yaml:"spacer,omitempty"`
+	FlexSpacer bool    `yaml:"flex-spacer,omitempty"`
+	Sort       Sort    `yaml:"sort,omitempty"`
+	Display    Display `yaml:"display,omitempty"`
+	View       View    `yaml:"view,omitempty"`
+}
+
+// Key identifies the item by its path, file or URL.
+func (f Folder) Key() string {
+	switch {
+	case f.URL != "":
+		return f.URL
+	case f.File != "":
+		return f.File
+	}
+	return spacerString(f.Path, f.Spacer, f.FlexSpacer)
 }
 
 type DockSettings struct {
@@ -31,17 +61,66 @@ type DockSettings struct {
 	AutoHide              bool `yaml:"autohide"`
 	ShowRecents           bool `yaml:"show-recents"`
 	SizeImmutable         bool `yaml:"size-immutable"`
+
+	// Settings below are left as they are in the Dock when unset.
+	Orientation                    *string `yaml:"orientation,omitempty"`
+	MinEffect                      *string `yaml:"mineffect,omitempty"`
+	LaunchAnim                     *bool   `yaml:"launchanim,omitempty"`
+	AutoHideDelay                  any     `yaml:"autohide-delay,omitempty"`
+	AutoHideTimeModifier           any     `yaml:"autohide-time-modifier,omitempty"`
+	ShowProcessIndicators          *bool   `yaml:"show-process-indicators,omitempty"`
+	StaticOnly                     *bool   `yaml:"static-only,omitempty"`
+	MRUSpaces                      *bool   `yaml:"mru-spaces,omitempty"`
+	ShowHidden                     *bool   `yaml:"showhidden,omitempty"`
+	ScrollToOpen                   *bool   `yaml:"scroll-to-open,omitempty"`
+	ContentsImmutable              *bool   `yaml:"contents-immutable,omitempty"`
+	PositionImmutable              *bool   `yaml:"position-immutable,omitempty"`
+	MagnifyImmutable               *bool   `yaml:"magnify-immutable,omitempty"`
+	MagSizeImmutable               *bool   `yaml:"magsize-immutable,omitempty"`
+	AutoHideImmutable              *bool   `yaml:"autohide-immutable,omitempty"`
+	MinEffectImmutable             *bool   `yaml:"mineffect-immutable,omitempty"`
+	LaunchAnimImmutable            *bool   `yaml:"launchanim-immutable,omitempty"`
+	ShowProcessIndicatorsImmutable *bool   `yaml:"show-process-indicators-immutable,omitempty"`
+	MinimizeToApplicationImmutable *bool   `yaml:"min-in-place-immutable,omitempty"`
 }
 
-func Load(file string) (Config, error) {
+var (
+	Orientations = []string{"bottom", "left", "right"}
+	MinEffects   = []string{"genie", "scale", "suck"}
+)
+
+// Managed returns a copy of s without the optional settings that want leaves
+// unset, so that settings a config does not mention are not compared. It is
+// nil when want is, as load leaves the settings alone then.
+func (s *DockSettings) Managed(want *DockSettings) *DockSettings {
+	if s == nil || want == nil {
+		return nil
+	}
+
+	out := *s
+	ov, wv := reflect.ValueOf(&out).Elem(), reflect.ValueOf(want).Elem()
+	for i := range ov.NumField() {
+		f := ov.Field(i)
+		if (f.Kind() == reflect.Pointer || f.Kind() == reflect.Interface) && wv.Field(i).IsNil() {
+			f.SetZero()
+		}
+	}
+	return &out
+}
+
+// Load reads the effective config of file, see ResolveFile.
+func Load(file string, opts LoadOptions) (Config, error) {
 	conf := new(Config)
 
-	data, err := os.ReadFile(file)
+	doc, err := ResolveFile(file, opts)
 	if err != nil {
 		return *conf, err
 	}
+	if len(doc.Content) == 0 {
+		return *conf, nil
+	}
 
-	if err := yaml.Unmarshal(data, conf); err != nil {
+	if err := doc.Content[0].Decode(conf); err != nil {
 		return *conf, err
 	}
 
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -43,10 +44,38 @@ func Test_Load(t *testing.T) {
 				tc.file = path
 			}
 
-			_, err := Load(tc.file)
+			_, err := Load(tc.file, LoadOptions{})
 			if (err != nil) != tc.wantErr {
 				t.Fatalf("Load(%s) error = %v, wantErr=%v", tc.file, err, tc.wantErr)
 			}
 		})
 	}
 }
+
+func Test_DockSettings_Managed(t *testing.T) {
+	t.Parallel()
+
+	left, right, yes := "left", "right", true
+	dock := &DockSettings{TileSize: 48, AutoHide: true, Orientation: &right, MRUSpaces: &yes}
+
+	tests := map[string]struct {
+		want *DockSettings
+		out  *DockSettings
+	}{
+		"no settings":      {want: nil, out: nil},
+		"nothing set":      {want: &DockSettings{}, out: &DockSettings{AutoHide: true}},
+		"orientation set":  {want: &DockSettings{Orientation: &left}, out: &DockSettings{AutoHide: true, Orientation: &right}},
+		"tile size set":    {want: &DockSettings{TileSize: 32}, out: &DockSettings{TileSize: 48, AutoHide: true}},
+		"all settings set": {want: dock, out: dock},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := dock.Managed(tc.want)
+			if !reflect.DeepEqual(got, tc.out) {
+				t.Fatalf("Managed = %+v, want %+v", got, tc.out)
+			}
+		})
+	}
+}
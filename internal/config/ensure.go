@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Mode is how dorg load treats the apps already in the Dock.
+type Mode string
+
+const (
+	// ModeExact makes the apps section exactly the listed apps.
+	ModeExact Mode = "exact"
+	// ModeEnsure only adds the present apps and removes the absent ones.
+	ModeEnsure Mode = "ensure"
+)
+
+var Modes = []string{string(ModeExact), string(ModeEnsure)}
+
+const (
+	PositionFirst = "first"
+	PositionLast  = "last"
+)
+
+var Positions = []string{PositionFirst, PositionLast}
+
+// EnsureApp is an app that has to be in the Dock in ensure mode. After and
+// Position say where it goes when it is missing; by default it goes last.
+type EnsureApp struct {
+	App      string `yaml:"app"`
+	After    string `yaml:"after,omitempty"`
+	Position string `yaml:"position,omitempty"`
+}
+
+type ensureApp EnsureApp
+
+func (e EnsureApp) MarshalYAML() (any, error) {
+	if e.After == "" && e.Position == "" {
+		return e.App, nil
+	}
+	return ensureApp(e), nil
+}
+
+// UnmarshalYAML accepts a plain app as well as a mapping with hints.
+func (e *EnsureApp) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.MappingNode {
+		var a ensureApp
+		if err := n.Decode(&a); err != nil {
+			return err
+		}
+		*e = EnsureApp(a)
+		return nil
+	}
+	*e = EnsureApp{}
+	return n.Decode(&e.App)
+}
+
+// ModeFor returns the mode the config is loaded in: override if set, else the
+// one the config asks for, else exact.
+func (c Config) ModeFor(override Mode) (Mode, error) {
+	mode := override
+	if mode == "" {
+		mode = c.Mode
+	}
+	if mode == "" {
+		mode = ModeExact
+	}
+
+	switch mode {
+	case ModeExact:
+		if len(c.Dock.Present) > 0 || len(c.Dock.Absent) > 0 {
+			return "", fmt.Errorf("'present' and 'absent' are only used in ensure mode")
+		}
+	case ModeEnsure:
+		if len(c.Dock.Apps) > 0 {
+			return "", fmt.Errorf("'apps' is only used in exact mode, list apps under 'present' instead")
+		}
+	default:
+		return "", fmt.Errorf("unknown mode '%s'", mode)
+	}
+	return mode, nil
+}
+
+// Ensure applies the present and absent apps to apps, the apps currently in
+// the Dock. Absent apps are removed and missing present apps inserted where
+// their hints say; every other app keeps its place.
+func (d Dock) Ensure(apps []App) []App {
+	out := slices.DeleteFunc(slices.Clone(apps), func(a App) bool {
+		return slices.ContainsFunc(d.Absent, func(path string) bool { return samePath(a, path) })
+	})
+
+	first := 0
+	for _, e := range d.Present {
+		if slices.ContainsFunc(out, func(a App) bool { return samePath(a, e.App) }) {
+			continue
+		}
+
+		i := len(out)
+		switch {
+		case e.Position == PositionFirst:
+			i = first
+			first++
+		case e.After != "":
+			if j := slices.IndexFunc(out, func(a App) bool { return samePath(a, e.After) }); j >= 0 {
+				i = j + 1
+			}
+		}
+		out = slices.Insert(out, i, App{Path: e.App})
+	}
+	return out
+}
+
+func samePath(a App, path string) bool {
+	return !a.IsSpacer() && filepath.Clean(a.Path) == filepath.Clean(path)
+}
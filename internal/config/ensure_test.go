@@ -0,0 +1,126 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func Test_EnsureApp_Unmarshal(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content string
+		want    []EnsureApp
+		wantErr bool
+	}{
+		"plain": {content: "[/A.app, Safari]", want: []EnsureApp{{App: "/A.app"}, {App: "Safari"}}},
+		"hints": {
+			content: "[{app: /A.app, after: Safari}, {app: /B.app, position: first}]",
+			want:    []EnsureApp{{App: "/A.app", After: "Safari"}, {App: "/B.app", Position: PositionFirst}},
+		},
+		"list": {content: "[[/A.app]]", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []EnsureApp
+			err := yaml.Unmarshal([]byte(tc.content), &got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Unmarshal error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Unmarshal = %+v, want %+v", got, tc.want)
+			}
+			if err != nil {
+				return
+			}
+			out, err := yaml.Marshal(got)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			var again []EnsureApp
+			if err := yaml.Unmarshal(out, &again); err != nil || !reflect.DeepEqual(again, tc.want) {
+				t.Fatalf("round trip = %+v (%v), want %+v", again, err, tc.want)
+			}
+		})
+	}
+}
+
+func Test_Config_ModeFor(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		conf     Config
+		override Mode
+		want     Mode
+		wantErr  bool
+	}{
+		"default":          {conf: Config{Dock: Dock{Apps: []App{{Path: "/A.app"}}}}, want: ModeExact},
+		"config":           {conf: Config{Mode: ModeEnsure, Dock: Dock{Present: []EnsureApp{{App: "/A.app"}}}}, want: ModeEnsure},
+		"override":         {conf: Config{Mode: ModeExact, Dock: Dock{Absent: []string{"/A.app"}}}, override: ModeEnsure, want: ModeEnsure},
+		"apps in ensure":   {conf: Config{Mode: ModeEnsure, Dock: Dock{Apps: []App{{Path: "/A.app"}}}}, wantErr: true},
+		"present in exact": {conf: Config{Dock: Dock{Present: []EnsureApp{{App: "/A.app"}}}}, wantErr: true},
+		"unknown":          {conf: Config{Mode: "merge"}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tc.conf.ModeFor(tc.override)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ModeFor error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Fatalf("ModeFor = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_Dock_Ensure(t *testing.T) {
+	t.Parallel()
+
+	current := []App{{Path: "/Safari.app"}, {Spacer: SpacerSmall}, {Path: "/Mail.app"}, {Path: "/Chess.app"}}
+
+	tests := map[string]struct {
+		dock Dock
+		want []string
+	}{
+		"nothing":      {dock: Dock{}, want: []string{"/Safari.app", "spacer: small", "/Mail.app", "/Chess.app"}},
+		"already here": {dock: Dock{Present: []EnsureApp{{App: "/Mail.app", Position: PositionFirst}}}, want: []string{"/Safari.app", "spacer: small", "/Mail.app", "/Chess.app"}},
+		"absent":       {dock: Dock{Absent: []string{"/Chess.app", "/Missing.app"}}, want: []string{"/Safari.app", "spacer: small", "/Mail.app"}},
+		"last":         {dock: Dock{Present: []EnsureApp{{App: "/Notes.app"}}}, want: []string{"/Safari.app", "spacer: small", "/Mail.app", "/Chess.app", "/Notes.app"}},
+		"first": {
+			dock: Dock{Present: []EnsureApp{{App: "/Notes.app", Position: PositionFirst}, {App: "/Music.app", Position: PositionFirst}}},
+			want: []string{"/Notes.app", "/Music.app", "/Safari.app", "spacer: small", "/Mail.app", "/Chess.app"},
+		},
+		"after": {
+			dock: Dock{Present: []EnsureApp{{App: "/Notes.app", After: "/Safari.app"}, {App: "/Music.app", After: "/Notes.app"}}},
+			want: []string{"/Safari.app", "/Notes.app", "/Music.app", "spacer: small", "/Mail.app", "/Chess.app"},
+		},
+		"after missing": {
+			dock: Dock{Present: []EnsureApp{{App: "/Notes.app", After: "/Missing.app"}}, Absent: []string{"/Chess.app"}},
+			want: []string{"/Safari.app", "spacer: small", "/Mail.app", "/Notes.app"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tc.dock.Ensure(current)
+			keys := make([]string, len(got))
+			for i, app := range got {
+				keys[i] = app.String()
+			}
+			if !reflect.DeepEqual(keys, tc.want) {
+				t.Fatalf("Ensure = %v, want %v", keys, tc.want)
+			}
+			if len(current) != 4 {
+				t.Fatalf("Ensure changed its input: %v", current)
+			}
+		})
+	}
+}
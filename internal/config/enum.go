@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Sort is the arrangement of a Dock folder's items.
+type Sort int
+
+const (
+	SortName         Sort = 1
+	SortDateAdded    Sort = 2
+	SortDateModified Sort = 3
+	SortDateCreated  Sort = 4
+	SortKind         Sort = 5
+)
+
+var sortNames = map[Sort]string{
+	SortName:         "name",
+	SortDateAdded:    "date-added",
+	SortDateModified: "date-modified",
+	SortDateCreated:  "date-created",
+	SortKind:         "kind",
+}
+
+// Display is how a Dock folder's tile looks.
+type Display int
+
+const (
+	DisplayStack  Display = 0
+	DisplayFolder Display = 1
+)
+
+var displayNames = map[Display]string{
+	DisplayStack:  "stack",
+	DisplayFolder: "folder",
+}
+
+// View is how a Dock folder's items are shown when it is opened.
+type View int
+
+const (
+	ViewAutomatic View = 0
+	ViewFan       View = 1
+	ViewGrid      View = 2
+	ViewList      View = 3
+)
+
+var viewNames = map[View]string{
+	ViewAutomatic: "automatic",
+	ViewFan:       "fan",
+	ViewGrid:      "grid",
+	ViewList:      "list",
+}
+
+func (s Sort) String() string                    { return enumString(s, sortNames) }
+func (s Sort) MarshalYAML() (any, error)         { return enumValue(s, sortNames), nil }
+func (s Sort) MarshalText() ([]byte, error)      { return []byte(s.String()), nil }
+func (s *Sort) UnmarshalYAML(n *yaml.Node) error { return parseEnum(n, sortNames, s) }
+
+func (d Display) String() string                    { return enumString(d, displayNames) }
+func (d Display) MarshalYAML() (any, error)         { return enumValue(d, displayNames), nil }
+func (d Display) MarshalText() ([]byte, error)      { return []byte(d.String()), nil }
+func (d *Display) UnmarshalYAML(n *yaml.Node) error { return parseEnum(n, displayNames, d) }
+
+func (v View) String() string                    { return enumString(v, viewNames) }
+func (v View) MarshalYAML() (any, error)         { return enumValue(v, viewNames), nil }
+func (v View) MarshalText() ([]byte, error)      { return []byte(v.String()), nil }
+func (v *View) UnmarshalYAML(n *yaml.Node) error { return parseEnum(n, viewNames, v) }
+
+func enumString[T ~int](v T, names map[T]string) string {
+	if name, ok := names[v]; ok {
+		return name
+	}
+	return strconv.Itoa(int(v))
+}
+
+func enumValue[T ~int](v T, names map[T]string) any {
+	if name, ok := names[v]; ok {
+		return name
+	}
+	return int(v)
+}
+
+// parseEnum accepts either one of the names or, for backward compatibility, the raw plist integer.
+func parseEnum[T ~int](n *yaml.Node, names map[T]string, out *T) error {
+	if n.Tag == "!!int" {
+		i, err := strconv.Atoi(n.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: '%s' is not an integer", n.Line, n.Value)
+		}
+		*out = T(i)
+		return nil
+	}
+	for v, name := range names {
+		if strings.EqualFold(name, n.Value) {
+			*out = v
+			return nil
+		}
+	}
+	return fmt.Errorf("line %d: '%s' must be one of %s", n.Line, n.Value, enumList(names))
+}
+
+func enumList[T ~int](names map[T]string) string {
+	keys := make([]T, 0, len(names))
+	for v := range names {
+		keys = append(keys, v)
+	}
+	slices.Sort(keys)
+
+	list := make([]string, len(keys))
+	for i, v := range keys {
+		list[i] = names[v]
+	}
+	return strings.Join(list, ", ")
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func Test_Folder_Unmarshal(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content string
+		want    Folder
+		wantErr bool
+	}{
+		"names":        {content: "sort: date-modified\ndisplay: folder\nview: grid\n", want: Folder{Sort: SortDateModified, Display: DisplayFolder, View: ViewGrid}},
+		"integers":     {content: "sort: 5\ndisplay: 1\nview: 3\n", want: Folder{Sort: SortKind, Display: DisplayFolder, View: ViewList}},
+		"mixed case":   {content: "sort: Kind\nview: FAN\n", want: Folder{Sort: SortKind, View: ViewFan}},
+		"unset sort":   {content: "sort: 0\n", want: Folder{}},
+		"unknown name": {content: "sort: size\n", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var got Folder
+			err := yaml.Unmarshal([]byte(tc.content), &got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Unmarshal error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if err == nil && got != tc.want {
+				t.Fatalf("Unmarshal = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_Folder_Marshal(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		folder Folder
+		want   []string
+	}{
+		"names":        {folder: Folder{Path: "~/Downloads", Sort: SortDateAdded, Display: DisplayFolder, View: ViewList}, want: []string{"sort: date-added", "display: folder", "view: list"}},
+		"defaults":     {folder: Folder{Path: "~/Downloads", Sort: SortName}, want: []string{"sort: name"}},
+		"out of range": {folder: Folder{Path: "~/Downloads", Sort: 9}, want: []string{"sort: 9"}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := yaml.Marshal(tc.folder)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(string(data), want) {
+					t.Fatalf("Marshal = %q, missing %q", data, want)
+				}
+			}
+
+			var back Folder
+			if err := yaml.Unmarshal(data, &back); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			if back != tc.folder {
+				t.Fatalf("round trip = %+v, want %+v", back, tc.folder)
+			}
+		})
+	}
+}
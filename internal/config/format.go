@@ -0,0 +1,367 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/5ouma/dorg/internal/remote"
+	"github.com/BurntSushi/toml"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Format is the syntax of a config file. Every format has the same sections
+// and keys.
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+	FormatTOML Format = "toml"
+)
+
+var Formats = []string{string(FormatYAML), string(FormatJSON), string(FormatTOML)}
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatYAML, FormatJSON, FormatTOML:
+		return f, nil
+	case "yml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("unknown format '%s', expected one of %s", s, strings.Join(Formats, ", "))
+}
+
+// FormatOf returns the format of a config file by its extension, ignoring
+// TemplateExt. Anything else, like standard input, is YAML.
+func FormatOf(file string) Format {
+	if remote.IsURL(file) {
+		if u, err := url.Parse(file); err == nil {
+			file = u.Path
+		}
+	}
+	switch strings.ToLower(filepath.Ext(strings.TrimSuffix(file, TemplateExt))) {
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	}
+	return FormatYAML
+}
+
+// formatFor returns format, or the format of file when empty.
+func formatFor(file string, format Format) Format {
+	if format == "" {
+		return FormatOf(file)
+	}
+	return format
+}
+
+// Decode parses data into a document node, like yaml.Unmarshal does for YAML.
+// JSON nodes keep their positions; TOML nodes have none.
+func Decode(format Format, data []byte) (*yaml.Node, error) {
+	if format != FormatJSON && format != FormatTOML {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		return &doc, nil
+	}
+
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Line: 1, Column: 1}
+	switch format {
+	case FormatJSON:
+		if len(bytes.TrimSpace(data)) == 0 {
+			return doc, nil
+		}
+		n, err := decodeJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		doc.Content = []*yaml.Node{n}
+	case FormatTOML:
+		var v map[string]any
+		md, err := toml.Decode(string(data), &v)
+		if err != nil {
+			return nil, err
+		}
+		if len(v) == 0 {
+			return doc, nil
+		}
+		doc.Content = []*yaml.Node{tomlNode(v, nil, tomlOrder(md))}
+	}
+	return doc, nil
+}
+
+// Encode writes the node n, a document or its root, in format.
+func Encode(format Format, n *yaml.Node) ([]byte, error) {
+	if n.Kind == yaml.DocumentNode {
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		n = n.Content[0]
+	}
+
+	switch format {
+	case FormatJSON:
+		var buf bytes.Buffer
+		if err := encodeJSON(&buf, n); err != nil {
+			return nil, err
+		}
+		var out bytes.Buffer
+		if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+			return nil, err
+		}
+		out.WriteByte('\n')
+		return out.Bytes(), nil
+	case FormatTOML:
+		var v any
+		if err := n.Decode(&v); err != nil {
+			return nil, err
+		}
+		m, ok := dropNulls(v).(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("TOML needs a mapping at the top level")
+		}
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(m); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(n); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Marshal writes v in format, with the keys of its YAML tags.
+func Marshal(format Format, v any) ([]byte, error) {
+	var n yaml.Node
+	if err := n.Encode(v); err != nil {
+		return nil, err
+	}
+	return Encode(format, &n)
+}
+
+// decodeJSON builds the node of a JSON value, keeping the order of keys and
+// the position of every value.
+func decodeJSON(data []byte) (*yaml.Node, error) {
+	d := &jsonDecoder{dec: json.NewDecoder(bytes.NewReader(data)), data: data}
+	d.dec.UseNumber()
+	n, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := d.dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after the top-level value")
+	}
+	return n, nil
+}
+
+type jsonDecoder struct {
+	dec  *json.Decoder
+	data []byte
+}
+
+// position returns the line and column of the next token.
+func (d *jsonDecoder) position() (int, int) {
+	offset := int(d.dec.InputOffset())
+	for offset < len(d.data) && strings.ContainsRune(" \t\r\n,:", rune(d.data[offset])) {
+		offset++
+	}
+	before := d.data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	return line, offset - bytes.LastIndexByte(before, '\n')
+}
+
+func (d *jsonDecoder) value() (*yaml.Node, error) {
+	line, column := d.position()
+	tok, err := d.dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON at line %d: %w", line, err)
+	}
+
+	n := &yaml.Node{Kind: yaml.ScalarNode, Line: line, Column: column}
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok == '{' {
+			n.Kind, n.Tag = yaml.MappingNode, "!!map"
+		} else {
+			n.Kind, n.Tag = yaml.SequenceNode, "!!seq"
+		}
+		for d.dec.More() {
+			if n.Kind == yaml.MappingNode {
+				key, err := d.value()
+				if err != nil {
+					return nil, err
+				}
+				n.Content = append(n.Content, key)
+			}
+			item, err := d.value()
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, item)
+		}
+		if _, err := d.dec.Token(); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+	case string:
+		n.Tag, n.Value = "!!str", tok
+	case json.Number:
+		n.Tag, n.Value = "!!int", tok.String()
+		if strings.ContainsAny(n.Value, ".eE") {
+			n.Tag = "!!float"
+		}
+	case bool:
+		n.Tag, n.Value = "!!bool", fmt.Sprint(tok)
+	case nil:
+		n.Tag, n.Value = "!!null", "null"
+	}
+	return n, nil
+}
+
+// encodeJSON writes n as compact JSON, keeping the order of keys.
+func encodeJSON(buf *bytes.Buffer, n *yaml.Node) error {
+	n = resolveAlias(n)
+	switch n.Kind {
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(n.Content[i].Value)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := encodeJSON(buf, n.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range n.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		var v any
+		if err := n.Decode(&v); err != nil {
+			return err
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
+
+// tomlOrder returns the position of every key in the document, so that
+// mappings keep the order of the file. Keys inside arrays share the path of
+// the array.
+func tomlOrder(md toml.MetaData) map[string]int {
+	order := map[string]int{}
+	for i, key := range md.Keys() {
+		path := strings.Join(key, "\x00")
+		if _, ok := order[path]; !ok {
+			order[path] = i
+		}
+	}
+	return order
+}
+
+func tomlNode(v any, path []string, order map[string]int) *yaml.Node {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.SortFunc(keys, func(a, b string) int {
+			ia, oka := order[strings.Join(append(slices.Clone(path), a), "\x00")]
+			ib, okb := order[strings.Join(append(slices.Clone(path), b), "\x00")]
+			switch {
+			case oka && okb && ia != ib:
+				return ia - ib
+			case oka != okb:
+				if oka {
+					return -1
+				}
+				return 1
+			}
+			return strings.Compare(a, b)
+		})
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, k := range keys {
+			n.Content = append(n.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k},
+				tomlNode(v[k], append(slices.Clone(path), k), order))
+		}
+		return n
+	case []map[string]any:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range v {
+			n.Content = append(n.Content, tomlNode(item, path, order))
+		}
+		return n
+	case []any:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range v {
+			n.Content = append(n.Content, tomlNode(item, path, order))
+		}
+		return n
+	}
+
+	var n yaml.Node
+	if err := n.Encode(v); err != nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fmt.Sprint(v)}
+	}
+	return &n
+}
+
+// dropNulls removes null values, which TOML cannot express.
+func dropNulls(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			if item != nil {
+				out[k] = dropNulls(item)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, 0, len(v))
+		for _, item := range v {
+			if item != nil {
+				out = append(out, dropNulls(item))
+			}
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,236 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func Test_FormatOf(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		file string
+		want Format
+	}{
+		"yaml":     {file: "dorg.yml", want: FormatYAML},
+		"json":     {file: "dorg.json", want: FormatJSON},
+		"toml":     {file: "conf/DORG.TOML", want: FormatTOML},
+		"template": {file: "dorg.json.tmpl", want: FormatJSON},
+		"url":      {file: "https://example.com/dorg.toml?ref=main", want: FormatTOML},
+		"stdin":    {file: "-", want: FormatYAML},
+		"unknown":  {file: "dorg.conf", want: FormatYAML},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := FormatOf(tc.file); got != tc.want {
+				t.Fatalf("FormatOf(%s) = %s, want %s", tc.file, got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_ParseFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		name    string
+		want    Format
+		wantErr bool
+	}{
+		"yaml":    {name: "yaml", want: FormatYAML},
+		"yml":     {name: "yml", want: FormatYAML},
+		"json":    {name: "JSON", want: FormatJSON},
+		"toml":    {name: "toml", want: FormatTOML},
+		"unknown": {name: "ini", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseFormat(tc.name)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseFormat error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Fatalf("ParseFormat(%s) = %s, want %s", tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_Decode(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		format  Format
+		data    string
+		want    string
+		wantErr bool
+	}{
+		"json": {
+			format: FormatJSON,
+			data:   "{\n\t\"dock_items\": {\"apps\": [\"/A.app\", {\"spacer\": \"small\"}], \"settings\": {\"tilesize\": 48, \"autohide\": true}},\n\t\"target\": null\n}",
+			want:   "dock_items:\n  apps:\n    - /A.app\n    - spacer: small\n  settings:\n    tilesize: 48\n    autohide: true\ntarget: null\n",
+		},
+		"json escapes": {
+			format: FormatJSON,
+			data:   `{"url": "https:\/\/example.com", "label": "café", "n": "1"}`,
+			want:   "url: https://example.com\nlabel: café\nn: \"1\"\n",
+		},
+		"toml keeps the order of keys": {
+			format: FormatTOML,
+			data:   "mode = \"ensure\"\n\n[dock_items]\npresent = [\"/B.app\", {app = \"/A.app\", position = \"first\"}]\nabsent = [\"/C.app\"]\n",
+			want:   "mode: ensure\ndock_items:\n  present:\n    - /B.app\n    - app: /A.app\n      position: first\n  absent:\n    - /C.app\n",
+		},
+		"toml arrays of tables": {
+			format: FormatTOML,
+			data:   "[[dock_items.others]]\npath = \"~/Downloads\"\nview = \"grid\"\n",
+			want:   "dock_items:\n  others:\n    - path: ~/Downloads\n      view: grid\n",
+		},
+		"empty json":   {format: FormatJSON, data: " \n", want: ""},
+		"empty toml":   {format: FormatTOML, data: "", want: ""},
+		"invalid json": {format: FormatJSON, data: `{"dock_items": }`, wantErr: true},
+		"trailing":     {format: FormatJSON, data: `{} {}`, wantErr: true},
+		"invalid toml": {format: FormatTOML, data: "dock_items = [", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := Decode(tc.format, []byte(tc.data))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Decode error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, err := Encode(FormatYAML, doc)
+			if err != nil {
+				t.Fatalf("Encode error: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("Decode =\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_Decode_JSONPositions(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Decode(FormatJSON, []byte("{\n  \"dock_items\": {\n    \"apps\": [\"/A.app\",\n      \"/B.app\"]\n  }\n}\n"))
+	if err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	apps := mappingValue(mappingValue(doc.Content[0], "dock_items"), "apps")
+	got := [][2]int{{apps.Line, apps.Column}}
+	for _, n := range apps.Content {
+		got = append(got, [2]int{n.Line, n.Column})
+	}
+	want := [][2]int{{3, 13}, {3, 14}, {4, 7}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("positions = %v, want %v", got, want)
+	}
+}
+
+func Test_Marshal(t *testing.T) {
+	t.Parallel()
+
+	orientation := "left"
+	conf := Config{
+		Mode: ModeEnsure,
+		Dock: Dock{
+			Others:   []Folder{{Path: "~/Downloads", Sort: SortName}, {Spacer: SpacerSmall}},
+			Settings: &DockSettings{TileSize: 48, AutoHide: true, Orientation: &orientation},
+			Present:  []EnsureApp{{App: "/A.app"}, {App: "/B.app", After: "/A.app"}},
+			Absent:   []string{"/C.app"},
+		},
+		HotCorners: HotCorners{"top-left": {Action: ActionMissionControl, Modifiers: Modifiers{ModifierCommand}}},
+	}
+
+	for _, format := range []Format{FormatYAML, FormatJSON, FormatTOML} {
+		t.Run(string(format), func(t *testing.T) {
+			t.Parallel()
+
+			data, err := Marshal(format, &conf)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			if !strings.Contains(string(data), "dock_items") || !strings.Contains(string(data), "hot_corners") {
+				t.Fatalf("Marshal did not use the YAML keys:\n%s", data)
+			}
+
+			file := filepath.Join(t.TempDir(), "dorg."+string(format))
+			if err := os.WriteFile(file, data, 0644); err != nil {
+				t.Fatalf("failed to write %s: %v", file, err)
+			}
+			got, err := Load(file, LoadOptions{Host: &Host{}})
+			if err != nil {
+				t.Fatalf("Load error: %v\n%s", err, data)
+			}
+			// Sizes are decoded as ints of whichever width the format has.
+			got.Dock.Settings.TileSize = conf.Dock.Settings.TileSize
+			if !reflect.DeepEqual(got, conf) {
+				t.Fatalf("Load = %+v, want %+v\n%s", got, conf, data)
+			}
+		})
+	}
+}
+
+func Test_Validate_Formats(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		file   string
+		format Format
+		data   string
+		want   []string
+	}{
+		"json": {
+			file: "dorg.json",
+			data: "{\n  \"dock_items\": {\"apps\": [\"/A.app\"], \"others\": [{\"path\": \"~\", \"view\": \"tiles\"}]}\n}\n",
+			want: []string{"dorg.json:2:71: 'tiles' must be one of"},
+		},
+		"toml without positions": {
+			file: "dorg.toml",
+			data: "[dock_items]\napps = [\"/A.app\"]\nicons = true\n",
+			want: []string{"dorg.toml: unknown key 'icons'"},
+		},
+		"format overrides the extension": {
+			file:   "dorg.conf",
+			format: FormatTOML,
+			data:   "[dock_items]\napps = \"/A.app\"\n",
+			want:   []string{"dorg.conf: expected a list"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			problems := ValidateBytes(tc.file, []byte(tc.data), ValidateOptions{SkipApps: true, Format: tc.format})
+			if len(problems) != len(tc.want) {
+				t.Fatalf("ValidateBytes = %v, want %v", problems, tc.want)
+			}
+			for i, p := range problems {
+				if !strings.HasPrefix(p.Error(), tc.want[i]) {
+					t.Fatalf("problem %d = %q, want prefix %q", i, p.Error(), tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_Encode_TOMLNeedsMapping(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Encode(FormatTOML, &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}); err == nil {
+		t.Fatalf("Encode of a list to TOML succeeded")
+	}
+}
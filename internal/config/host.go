@@ -0,0 +1,42 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/5ouma/dorg/internal/runner"
+)
+
+// Host describes the Mac a config is loaded on.
+type Host struct {
+	Hostname  string
+	Model     string
+	OSVersion string
+	User      string
+}
+
+// DetectHost looks up the current host. Whatever cannot be found is left empty.
+func DetectHost(r runner.Runner) Host {
+	var h Host
+	if name, err := os.Hostname(); err == nil {
+		h.Hostname = strings.TrimSuffix(name, ".local")
+	}
+	if u, err := user.Current(); err == nil {
+		h.User = u.Username
+	}
+	h.Model = hostValue(r, "/usr/sbin/sysctl", "-n", "hw.model")
+	h.OSVersion = hostValue(r, "/usr/bin/sw_vers", "-productVersion")
+	return h
+}
+
+func hostValue(r runner.Runner, cmd string, args ...string) string {
+	out, err := r.Run(context.Background(), cmd, args...)
+	if err != nil {
+		slog.Debug("unable to detect host", "cmd", cmd, "error", err)
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"slices"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Corners are the screen corners a hot corner can be set on, in display order.
+var Corners = []string{"top-left", "top-right", "bottom-left", "bottom-right"}
+
+// HotCorners maps a corner name to what happens when the pointer reaches it.
+type HotCorners map[string]HotCorner
+
+type HotCorner struct {
+	Action    CornerAction `yaml:"action"`
+	Modifiers Modifiers    `yaml:"modifiers,omitempty"`
+}
+
+// Managed returns the corners of h that want also sets, so that corners a
+// config does not mention are not compared.
+func (h HotCorners) Managed(want HotCorners) HotCorners {
+	var out HotCorners
+	for name, corner := range h {
+		if _, ok := want[name]; !ok {
+			continue
+		}
+		if out == nil {
+			out = HotCorners{}
+		}
+		out[name] = corner
+	}
+	return out
+}
+
+// CornerAction is the wvous-*-corner value of a hot corner.
+type CornerAction int
+
+const (
+	ActionNone               CornerAction = 1
+	ActionMissionControl     CornerAction = 2
+	ActionApplicationWindows CornerAction = 3
+	ActionDesktop            CornerAction = 4
+	ActionStartScreenSaver   CornerAction = 5
+	ActionDisableScreenSaver CornerAction = 6
+	ActionDashboard          CornerAction = 7
+	ActionDisplaySleep       CornerAction = 10
+	ActionLaunchpad          CornerAction = 11
+	ActionNotificationCenter CornerAction = 12
+	ActionLockScreen         CornerAction = 13
+	ActionQuickNote          CornerAction = 14
+)
+
+var cornerActionNames = map[CornerAction]string{
+	ActionNone:               "none",
+	ActionMissionControl:     "mission-control",
+	ActionApplicationWindows: "application-windows",
+	ActionDesktop:            "desktop",
+	ActionStartScreenSaver:   "start-screen-saver",
+	ActionDisableScreenSaver: "disable-screen-saver",
+	ActionDashboard:          "dashboard",
+	ActionDisplaySleep:       "display-sleep",
+	ActionLaunchpad:          "launchpad",
+	ActionNotificationCenter: "notification-center",
+	ActionLockScreen:         "lock-screen",
+	ActionQuickNote:          "quick-note",
+}
+
+// Modifier is a key that has to be held for a hot corner to trigger; its
+// value is the key's bit in wvous-*-modifier.
+type Modifier int
+
+const (
+	ModifierShift   Modifier = 1 << 17
+	ModifierControl Modifier = 1 << 18
+	ModifierOption  Modifier = 1 << 19
+	ModifierCommand Modifier = 1 << 20
+)
+
+var modifierNames = map[Modifier]string{
+	ModifierShift:   "shift",
+	ModifierControl: "control",
+	ModifierOption:  "option",
+	ModifierCommand: "command",
+}
+
+func (a CornerAction) String() string                    { return enumString(a, cornerActionNames) }
+func (a CornerAction) MarshalYAML() (any, error)         { return enumValue(a, cornerActionNames), nil }
+func (a CornerAction) MarshalText() ([]byte, error)      { return []byte(a.String()), nil }
+func (a *CornerAction) UnmarshalYAML(n *yaml.Node) error { return parseEnum(n, cornerActionNames, a) }
+
+func (m Modifier) String() string                    { return enumString(m, modifierNames) }
+func (m Modifier) MarshalYAML() (any, error)         { return enumValue(m, modifierNames), nil }
+func (m Modifier) MarshalText() ([]byte, error)      { return []byte(m.String()), nil }
+func (m *Modifier) UnmarshalYAML(n *yaml.Node) error { return parseEnum(n, modifierNames, m) }
+
+type Modifiers []Modifier
+
+func (m Modifiers) String() string {
+	names := make([]string, len(m))
+	for i, modifier := range m {
+		names[i] = modifier.String()
+	}
+	return strings.Join(names, "+")
+}
+
+// Mask combines the modifiers into a wvous-*-modifier value.
+func (m Modifiers) Mask() int {
+	mask := 0
+	for _, modifier := range m {
+		mask |= int(modifier)
+	}
+	return mask
+}
+
+// ModifiersFromMask splits a wvous-*-modifier value into its modifiers. Bits
+// dorg has no name for are kept together as one extra value.
+func ModifiersFromMask(mask int) Modifiers {
+	var out Modifiers
+	for _, modifier := range []Modifier{ModifierShift, ModifierControl, ModifierOption, ModifierCommand} {
+		if mask&int(modifier) != 0 {
+			out = append(out, modifier)
+			mask &^= int(modifier)
+		}
+	}
+	if mask != 0 {
+		out = append(out, Modifier(mask))
+	}
+	slices.Sort(out)
+	return out
+}
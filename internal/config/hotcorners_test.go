@@ -0,0 +1,93 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func Test_HotCorners_Unmarshal(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content string
+		want    HotCorners
+		wantErr bool
+	}{
+		"names": {
+			content: "top-left:\n  action: mission-control\nbottom-right:\n  action: quick-note\n  modifiers: [command, option]\n",
+			want: HotCorners{
+				"top-left":     {Action: ActionMissionControl},
+				"bottom-right": {Action: ActionQuickNote, Modifiers: Modifiers{ModifierCommand, ModifierOption}},
+			},
+		},
+		"integers":         {content: "top-right:\n  action: 13\n", want: HotCorners{"top-right": {Action: ActionLockScreen}}},
+		"unknown action":   {content: "top-left:\n  action: explode\n", wantErr: true},
+		"unknown modifier": {content: "top-left:\n  action: desktop\n  modifiers: [hyper]\n", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var got HotCorners
+			err := yaml.Unmarshal([]byte(tc.content), &got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Unmarshal error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Unmarshal = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_ModifiersFromMask(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		mask int
+		want Modifiers
+	}{
+		"none":         {mask: 0, want: nil},
+		"command":      {mask: 1048576, want: Modifiers{ModifierCommand}},
+		"shift option": {mask: 131072 | 524288, want: Modifiers{ModifierShift, ModifierOption}},
+		"unknown bits": {mask: 1048576 | 1, want: Modifiers{1, ModifierCommand}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := ModifiersFromMask(tc.mask)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("ModifiersFromMask(%d) = %v, want %v", tc.mask, got, tc.want)
+			}
+			if got.Mask() != tc.mask {
+				t.Fatalf("Mask() = %d, want %d", got.Mask(), tc.mask)
+			}
+		})
+	}
+}
+
+func Test_HotCorners_Managed(t *testing.T) {
+	t.Parallel()
+
+	dock := HotCorners{"top-left": {Action: ActionDesktop}, "bottom-right": {Action: ActionQuickNote}}
+	tests := map[string]struct {
+		want HotCorners
+		out  HotCorners
+	}{
+		"nothing set":   {want: nil, out: nil},
+		"one corner":    {want: HotCorners{"top-left": {Action: ActionLockScreen}}, out: HotCorners{"top-left": {Action: ActionDesktop}}},
+		"unset in dock": {want: HotCorners{"top-right": {Action: ActionLockScreen}}, out: nil},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := dock.Managed(tc.want); !reflect.DeepEqual(got, tc.out) {
+				t.Fatalf("Managed = %v, want %v", got, tc.out)
+			}
+		})
+	}
+}
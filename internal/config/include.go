@@ -0,0 +1,191 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/5ouma/dorg/internal/remote"
+	yaml "gopkg.in/yaml.v3"
+)
+
+const includeKey = "include"
+
+// Reader reads a config file by name. os.ReadFile is used when nil.
+type Reader func(name string) ([]byte, error)
+
+func (r Reader) read(name string) ([]byte, error) {
+	if r == nil {
+		return os.ReadFile(name)
+	}
+	return r(name)
+}
+
+// loadDocument reads file, in format or by its extension when empty, and
+// merges the files it includes under it, in order, so that its own sections
+// come last.
+func loadDocument(read Reader, file string, format Format) (*yaml.Node, error) {
+	doc, err := readDocument(read, file, formatFor(file, format))
+	if err != nil {
+		return nil, err
+	}
+	id, err := includeID(file)
+	if err != nil {
+		return nil, err
+	}
+	return flatten(read, doc, file, []string{id})
+}
+
+func readDocument(read Reader, file string, format Format) (*yaml.Node, error) {
+	data, err := read.read(file)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := Decode(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return doc, nil
+}
+
+// flatten replaces the include entry of doc with the documents it names.
+// stack holds the absolute paths of the files being included, to detect cycles.
+func flatten(read Reader, doc *yaml.Node, file string, stack []string) (*yaml.Node, error) {
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return doc, nil
+	}
+	root := doc.Content[0]
+	i := mappingIndex(root, includeKey)
+	if i < 0 {
+		return doc, nil
+	}
+
+	local := *root
+	local.Content = slices.Delete(slices.Clone(root.Content), i, i+2)
+
+	var merged *yaml.Node
+	for _, entry := range includeEntries(root.Content[i+1]) {
+		path := includePath(file, entry.Value)
+		id, err := includeID(path)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(stack, id) {
+			return nil, includeProblem(file, entry, "include cycle: %s", strings.Join(append(slices.Clone(stack[slices.Index(stack, id):]), id), " -> "))
+		}
+
+		inc, err := readDocument(read, path, FormatOf(path))
+		if err != nil {
+			return nil, includeProblem(file, entry, "unable to include '%s': %v", entry.Value, err)
+		}
+		if inc, err = flatten(read, inc, path, append(slices.Clone(stack), id)); err != nil {
+			return nil, err
+		}
+		if len(inc.Content) == 0 {
+			continue
+		}
+		if inc.Content[0].Kind != yaml.MappingNode {
+			return nil, includeProblem(file, entry, "included file '%s' is not a mapping", entry.Value)
+		}
+		merged = mergeDocuments(merged, inc.Content[0])
+	}
+
+	out := *doc
+	out.Content = []*yaml.Node{mergeDocuments(merged, &local)}
+	return &out, nil
+}
+
+// mergeDocuments merges the root mapping over onto base. Profiles of the same
+// name are replaced and overlays appended, so that their list operations are
+// kept for Resolve; every other section is merged.
+func mergeDocuments(base, over *yaml.Node) *yaml.Node {
+	if base == nil {
+		return over
+	}
+
+	out := *base
+	out.Content = slices.Clone(base.Content)
+	for i := 0; i+1 < len(over.Content); i += 2 {
+		key, value := over.Content[i], over.Content[i+1]
+		j := mappingIndex(&out, key.Value)
+		if j < 0 {
+			out.Content = append(out.Content, key, nil)
+			j = len(out.Content) - 2
+		}
+		prev := out.Content[j+1]
+		switch {
+		case key.Value != profilesKey && key.Value != overlaysKey:
+			out.Content[j+1] = merge(prev, value)
+		case prev == nil:
+			out.Content[j+1] = value
+		case key.Value == profilesKey:
+			out.Content[j+1] = replaceKeys(prev, value)
+		default:
+			overlays := *resolveAlias(prev)
+			overlays.Content = append(slices.Clone(overlays.Content), resolveAlias(value).Content...)
+			out.Content[j+1] = &overlays
+		}
+	}
+	return &out
+}
+
+// replaceKeys returns base with the entries of over set, without merging them.
+func replaceKeys(base, over *yaml.Node) *yaml.Node {
+	base, over = resolveAlias(base), resolveAlias(over)
+	out := *base
+	out.Content = slices.Clone(base.Content)
+	for i := 0; i+1 < len(over.Content); i += 2 {
+		if j := mappingIndex(&out, over.Content[i].Value); j >= 0 {
+			out.Content[j+1] = over.Content[i+1]
+			continue
+		}
+		out.Content = append(out.Content, over.Content[i], over.Content[i+1])
+	}
+	return &out
+}
+
+// includeEntries accepts a single file as well as a list of them.
+func includeEntries(n *yaml.Node) []*yaml.Node {
+	n = resolveAlias(n)
+	if n.Kind == yaml.SequenceNode {
+		return n.Content
+	}
+	return []*yaml.Node{n}
+}
+
+// includePath resolves an include entry of file: relative paths are relative
+// to the directory or URL of file.
+func includePath(file, entry string) string {
+	if remote.IsURL(entry) {
+		return entry
+	}
+	if remote.IsURL(file) {
+		if base, err := url.Parse(file); err == nil {
+			if ref, err := url.Parse(entry); err == nil {
+				return base.ResolveReference(ref).String()
+			}
+		}
+	}
+
+	home, _ := os.UserHomeDir()
+	path := ExpandPath(entry, home)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(file), path)
+	}
+	return path
+}
+
+// includeID identifies a config file for cycle detection.
+func includeID(file string) (string, error) {
+	if !remote.IsLocal(file) {
+		return file, nil
+	}
+	return filepath.Abs(file)
+}
+
+func includeProblem(file string, n *yaml.Node, format string, args ...any) Problem {
+	return Problem{File: file, Line: n.Line, Column: n.Column, Message: fmt.Sprintf(format, args...)}
+}
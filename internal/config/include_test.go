@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Load_Include(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		files   map[string]string
+		profile string
+		want    Config
+		wantErr string
+	}{
+		"merged in order": {
+			files: map[string]string{
+				"dorg.yml":           "include: [shared/company.yml, shared/design.yml]\ndock_items:\n  apps: {append: [/C.app]}\n",
+				"shared/company.yml": "dock_items:\n  apps: [/A.app]\n  settings: {autohide: true}\n",
+				"shared/design.yml":  "dock_items:\n  apps: {append: [/B.app]}\n",
+			},
+			want: Config{Dock: Dock{Apps: appList("/A.app", "/B.app", "/C.app"), Settings: &DockSettings{AutoHide: true}}},
+		},
+		"local wins": {
+			files: map[string]string{
+				"dorg.yml":    "include: company.yml\ndock_items:\n  apps: [/C.app]\n",
+				"company.yml": "dock_items:\n  apps: [/A.app]\n",
+			},
+			want: Config{Dock: Dock{Apps: appList("/C.app")}},
+		},
+		"nested relative to the including file": {
+			files: map[string]string{
+				"dorg.yml": "include: [a/a.yml]\n",
+				"a/a.yml":  "include: [../b/b.yml]\ndock_items: {apps: {append: [/A.app]}}\n",
+				"b/b.yml":  "dock_items: {apps: [/B.app]}\n",
+			},
+			want: Config{Dock: Dock{Apps: appList("/B.app", "/A.app")}},
+		},
+		"profiles from fragments": {
+			files: map[string]string{
+				"dorg.yml":    "include: [company.yml]\nprofiles:\n  local: {dock_items: {apps: {append: [/L.app]}}}\n",
+				"company.yml": "dock_items: {apps: [/A.app]}\nprofiles:\n  dev: {dock_items: {apps: {append: [/D.app]}}}\n",
+			},
+			profile: "dev",
+			want:    Config{Dock: Dock{Apps: appList("/A.app", "/D.app")}},
+		},
+		"cycle": {
+			files: map[string]string{
+				"dorg.yml": "include: [a.yml]\n",
+				"a.yml":    "dock_items: {apps: [/A.app]}\ninclude: [b.yml]\n",
+				"b.yml":    "include:\n  - a.yml\n",
+			},
+			wantErr: "b.yml:2:5: include cycle:",
+		},
+		"missing file": {
+			files:   map[string]string{"dorg.yml": "dock_items: {apps: [/A.app]}\ninclude: [missing.yml]\n"},
+			wantErr: "dorg.yml:2:11: unable to include 'missing.yml'",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			for file, content := range tc.files {
+				path := filepath.Join(dir, file)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf("failed to create dir: %v", err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatalf("failed to write %s: %v", file, err)
+				}
+			}
+
+			got, err := Load(filepath.Join(dir, "dorg.yml"), LoadOptions{Profile: tc.profile, Host: &Host{}})
+			if tc.wantErr != "" {
+				var problem Problem
+				if !errors.As(err, &problem) || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("Load error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Load = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
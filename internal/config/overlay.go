@@ -0,0 +1,264 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/5ouma/dorg/internal/runner"
+	yaml "gopkg.in/yaml.v3"
+)
+
+const (
+	profilesKey = "profiles"
+	overlaysKey = "overlays"
+	matchKey    = "match"
+)
+
+// ListOps are the keys of a mapping that edits a list of the base config
+// instead of replacing it. They are applied in this order.
+var ListOps = []string{"replace", "remove", "prepend", "append"}
+
+type LoadOptions struct {
+	// Profile names the entry of profiles to merge onto the base config.
+	Profile string
+	// Host is matched against overlays. It is detected when nil.
+	Host *Host
+	// Read reads the config file and the files it includes.
+	Read Reader
+	// Vars are the variables of templates. They are detected when nil.
+	Vars *TemplateData
+	// Home is the home directory templates see, the current user's when empty.
+	Home string
+	// Format is the format of the config file. It is told by the extension
+	// when empty; included files always are.
+	Format Format
+}
+
+// Match selects the hosts an overlay applies to. Every field set is a glob
+// pattern that has to match.
+type Match struct {
+	Hostname string `yaml:"hostname"`
+	Model    string `yaml:"model"`
+	OS       string `yaml:"os"`
+	User     string `yaml:"user"`
+}
+
+func (m Match) Matches(h Host) bool {
+	return globMatch(strings.ToLower(m.Hostname), strings.ToLower(h.Hostname)) &&
+		globMatch(m.Model, h.Model) &&
+		globMatch(m.OS, h.OSVersion) &&
+		globMatch(m.User, h.User)
+}
+
+func globMatch(pattern, s string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, err := path.Match(pattern, s)
+	return err == nil && ok
+}
+
+// ResolveFile reads a config file and returns its effective document: the
+// files it includes and the file itself, rendered when they are templates,
+// with the selected profile and the overlays matching the host merged onto
+// them.
+func ResolveFile(file string, opts LoadOptions) (*yaml.Node, error) {
+	doc, err := loadDocument(opts.Read.rendering(templateVars(opts.Vars, opts.Host, opts.Home)), file, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	return Resolve(doc, opts)
+}
+
+// Resolve is ResolveFile for a parsed document.
+func Resolve(doc *yaml.Node, opts LoadOptions) (*yaml.Node, error) {
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		if opts.Profile != "" {
+			return nil, unknownProfile(opts.Profile, nil)
+		}
+		return doc, nil
+	}
+	root := doc.Content[0]
+
+	base := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: root.Line, Column: root.Column}
+	var profiles, overlays *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		switch key, value := root.Content[i], root.Content[i+1]; key.Value {
+		case profilesKey:
+			profiles = value
+		case overlaysKey:
+			overlays = value
+		default:
+			base.Content = append(base.Content, key, value)
+		}
+	}
+
+	if opts.Profile != "" {
+		profile := mappingValue(profiles, opts.Profile)
+		if profile == nil {
+			return nil, unknownProfile(opts.Profile, profiles)
+		}
+		base = merge(base, profile)
+	}
+
+	if overlays != nil && len(overlays.Content) > 0 {
+		host := opts.Host
+		if host == nil {
+			detected := DetectHost(runner.Exec{})
+			host = &detected
+		}
+		for _, overlay := range overlays.Content {
+			var m Match
+			if n := mappingValue(overlay, matchKey); n != nil {
+				if err := n.Decode(&m); err != nil {
+					return nil, err
+				}
+			}
+			if !m.Matches(*host) {
+				continue
+			}
+			body := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for i := 0; i+1 < len(overlay.Content); i += 2 {
+				if overlay.Content[i].Value != matchKey {
+					body.Content = append(body.Content, overlay.Content[i], overlay.Content[i+1])
+				}
+			}
+			base = merge(base, body)
+		}
+	}
+
+	return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{base}}, nil
+}
+
+// merge returns over merged onto base. Mappings are merged key by key, lists
+// are replaced unless over is a mapping of ListOps, and values are replaced.
+func merge(base, over *yaml.Node) *yaml.Node {
+	base, over = resolveAlias(base), resolveAlias(over)
+	if isListOps(over) && (base == nil || base.Kind == yaml.SequenceNode) {
+		return applyListOps(base, over)
+	}
+	if over.Kind != yaml.MappingNode {
+		return over
+	}
+	if base == nil || base.Kind != yaml.MappingNode {
+		base = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: over.Line, Column: over.Column}
+	}
+
+	out := *base
+	out.Content = slices.Clone(base.Content)
+	for i := 0; i+1 < len(over.Content); i += 2 {
+		key, value := over.Content[i], over.Content[i+1]
+		if j := mappingIndex(&out, key.Value); j >= 0 {
+			out.Content[j+1] = merge(out.Content[j+1], value)
+			continue
+		}
+		out.Content = append(out.Content, key, merge(nil, value))
+	}
+	return &out
+}
+
+func isListOps(n *yaml.Node) bool {
+	if n.Kind != yaml.MappingNode || len(n.Content) == 0 {
+		return false
+	}
+	for i := 0; i < len(n.Content); i += 2 {
+		if !slices.Contains(ListOps, n.Content[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func applyListOps(base, ops *yaml.Node) *yaml.Node {
+	out := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: ops.Line, Column: ops.Column}
+	if base != nil {
+		out.Content = slices.Clone(base.Content)
+	}
+	for _, op := range ListOps {
+		value := resolveAlias(mappingValue(ops, op))
+		if value == nil {
+			continue
+		}
+		items := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			items = value.Content
+		}
+		switch op {
+		case "replace":
+			out.Content = slices.Clone(items)
+		case "remove":
+			out.Content = slices.DeleteFunc(out.Content, func(n *yaml.Node) bool {
+				return slices.ContainsFunc(items, func(item *yaml.Node) bool { return nodeEqual(n, item) })
+			})
+		case "prepend":
+			out.Content = append(slices.Clone(items), out.Content...)
+		case "append":
+			out.Content = append(out.Content, items...)
+		}
+	}
+	return out
+}
+
+func nodeEqual(a, b *yaml.Node) bool {
+	a, b = resolveAlias(a), resolveAlias(b)
+	if a.Kind != b.Kind || len(a.Content) != len(b.Content) {
+		return false
+	}
+	if a.Kind == yaml.ScalarNode {
+		return a.Value == b.Value
+	}
+	for i := range a.Content {
+		if !nodeEqual(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func resolveAlias(n *yaml.Node) *yaml.Node {
+	if n != nil && n.Kind == yaml.AliasNode {
+		return n.Alias
+	}
+	return n
+}
+
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if i := mappingIndex(n, key); i >= 0 {
+		return n.Content[i+1]
+	}
+	return nil
+}
+
+// mappingIndex returns the index of key in the content of n, or -1.
+func mappingIndex(n *yaml.Node, key string) int {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func unknownProfile(name string, profiles *yaml.Node) error {
+	names := mappingKeys(profiles)
+	if len(names) == 0 {
+		return fmt.Errorf("unknown profile '%s': the config has no profiles", name)
+	}
+	return fmt.Errorf("unknown profile '%s', expected one of %s", name, strings.Join(names, ", "))
+}
+
+func mappingKeys(n *yaml.Node) []string {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	var keys []string
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		keys = append(keys, n.Content[i].Value)
+	}
+	return keys
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func Test_Resolve(t *testing.T) {
+	t.Parallel()
+
+	const base = `dock_items:
+  apps: [/A.app, /B.app]
+  settings: {autohide: true, tilesize: 48}
+profiles:
+  append: {dock_items: {apps: {append: [/C.app]}}}
+  prepend: {dock_items: {apps: {prepend: [/C.app]}}}
+  remove: {dock_items: {apps: {remove: [/A.app], append: [/D.app]}}}
+  replace: {dock_items: {apps: [/C.app]}}
+  settings: {dock_items: {settings: {tilesize: 64}}, hot_corners: {top-left: {action: desktop}}}
+overlays:
+  - match: {hostname: "front-desk-*"}
+    dock_items: {apps: {append: [/Kiosk.app]}}
+  - match: {os: "14.*", user: admin}
+    dock_items: {others: {append: [{path: /Applications}]}}
+`
+	host := &Host{Hostname: "Front-Desk-2", Model: "Mac14,2", OSVersion: "14.5", User: "kiosk"}
+
+	tests := map[string]struct {
+		profile string
+		host    *Host
+		want    Config
+		wantErr bool
+	}{
+		"base": {
+			host: &Host{},
+			want: Config{Dock: Dock{Apps: appList("/A.app", "/B.app"), Settings: &DockSettings{AutoHide: true, TileSize: 48}}},
+		},
+		"append":  {profile: "append", host: &Host{}, want: Config{Dock: Dock{Apps: appList("/A.app", "/B.app", "/C.app"), Settings: &DockSettings{AutoHide: true, TileSize: 48}}}},
+		"prepend": {profile: "prepend", host: &Host{}, want: Config{Dock: Dock{Apps: appList("/C.app", "/A.app", "/B.app"), Settings: &DockSettings{AutoHide: true, TileSize: 48}}}},
+		"remove":  {profile: "remove", host: &Host{}, want: Config{Dock: Dock{Apps: appList("/B.app", "/D.app"), Settings: &DockSettings{AutoHide: true, TileSize: 48}}}},
+		"replace": {profile: "replace", host: &Host{}, want: Config{Dock: Dock{Apps: appList("/C.app"), Settings: &DockSettings{AutoHide: true, TileSize: 48}}}},
+		"merged mappings": {
+			profile: "settings",
+			host:    &Host{},
+			want: Config{
+				Dock:       Dock{Apps: appList("/A.app", "/B.app"), Settings: &DockSettings{AutoHide: true, TileSize: 64}},
+				HotCorners: HotCorners{"top-left": {Action: ActionDesktop}},
+			},
+		},
+		"matching overlay": {
+			profile: "append",
+			host:    host,
+			want:    Config{Dock: Dock{Apps: appList("/A.app", "/B.app", "/C.app", "/Kiosk.app"), Settings: &DockSettings{AutoHide: true, TileSize: 48}}},
+		},
+		"every field has to match": {
+			host: &Host{OSVersion: "14.5", User: "admin"},
+			want: Config{Dock: Dock{Apps: appList("/A.app", "/B.app"), Others: []Folder{{Path: "/Applications"}}, Settings: &DockSettings{AutoHide: true, TileSize: 48}}},
+		},
+		"unknown profile": {profile: "missing", host: &Host{}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var doc yaml.Node
+			if err := yaml.Unmarshal([]byte(base), &doc); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			resolved, err := Resolve(&doc, LoadOptions{Profile: tc.profile, Host: tc.host})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Resolve error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var got Config
+			if err := resolved.Content[0].Decode(&got); err != nil {
+				t.Fatalf("Decode error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Resolve = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_Match_Matches(t *testing.T) {
+	t.Parallel()
+
+	host := Host{Hostname: "Design-01", Model: "MacBookPro18,1", OSVersion: "15.1", User: "ana"}
+
+	tests := map[string]struct {
+		match Match
+		want  bool
+	}{
+		"empty":             {match: Match{}, want: true},
+		"hostname any case": {match: Match{Hostname: "design-*"}, want: true},
+		"model":             {match: Match{Model: "MacBookPro*"}, want: true},
+		"os":                {match: Match{OS: "15.*"}, want: true},
+		"other os":          {match: Match{OS: "14.*"}, want: false},
+		"all fields":        {match: Match{Hostname: "Design-??", Model: "MacBook*", OS: "15.1", User: "ana"}, want: true},
+		"one field differs": {match: Match{Hostname: "Design-*", User: "bob"}, want: false},
+		"bad pattern":       {match: Match{User: "[a"}, want: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.match.Matches(host); got != tc.want {
+				t.Fatalf("Matches = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func appList(paths ...string) []App {
+	out := make([]App, len(paths))
+	for i, path := range paths {
+		out[i] = App{Path: path}
+	}
+	return out
+}
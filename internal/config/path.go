@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExpandPath expands $VAR and ${VAR} references and a leading "~" in path.
+// home stands in for both "~" and $HOME so that another user's Dock can be targeted.
+func ExpandPath(path, home string) string {
+	path = os.Expand(path, func(key string) string {
+		if key == "HOME" && home != "" {
+			return home
+		}
+		return os.Getenv(key)
+	})
+
+	if path == "~" {
+		return home
+	}
+	if after, ok := strings.CutPrefix(path, "~/"); ok {
+		return filepath.Join(home, after)
+	}
+	return path
+}
+
+// ContractPath replaces home at the start of path with "~". Paths outside
+// home are returned unchanged.
+func ContractPath(path, home string) string {
+	if home == "" {
+		return path
+	}
+	home = filepath.Clean(home)
+	if path == home {
+		return "~"
+	}
+	if after, ok := strings.CutPrefix(path, home+string(filepath.Separator)); ok {
+		return filepath.Join("~", after)
+	}
+	return path
+}
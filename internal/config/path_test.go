@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+)
+
+func Test_ExpandPath(t *testing.T) {
+	t.Setenv("DORG_TEST_VOLUME", "/Volumes/Shared")
+
+	tests := map[string]struct {
+		path string
+		want string
+	}{
+		"absolute":       {path: "/Applications", want: "/Applications"},
+		"tilde":          {path: "~", want: "/home/target"},
+		"tilde sub":      {path: "~/Documents", want: "/home/target/Documents"},
+		"home variable":  {path: "$HOME/Downloads", want: "/home/target/Downloads"},
+		"braced":         {path: "${DORG_TEST_VOLUME}/Projects", want: "/Volumes/Shared/Projects"},
+		"unset variable": {path: "$DORG_TEST_UNSET/Projects", want: "/Projects"},
+		"relative":       {path: "Documents", want: "Documents"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := ExpandPath(tc.path, "/home/target"); got != tc.want {
+				t.Fatalf("ExpandPath(%s) = %s, want %s", tc.path, got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_ContractPath(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		path string
+		home string
+		want string
+	}{
+		"home":         {path: "/Users/me", home: "/Users/me", want: "~"},
+		"under home":   {path: "/Users/me/Downloads", home: "/Users/me/", want: "~/Downloads"},
+		"outside home": {path: "/Applications", home: "/Users/me", want: "/Applications"},
+		"home prefix":  {path: "/Users/meg/Downloads", home: "/Users/me", want: "/Users/meg/Downloads"},
+		"no home":      {path: "/Users/me/Downloads", home: "", want: "/Users/me/Downloads"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ContractPath(tc.path, tc.home); got != tc.want {
+				t.Fatalf("ContractPath(%s, %s) = %s, want %s", tc.path, tc.home, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/5ouma/dorg/internal/remote"
+	"github.com/5ouma/dorg/internal/runner"
+)
+
+// TemplateExt marks a config file that is rendered with text/template before
+// it is parsed, as in dorg.yml.tmpl.
+const TemplateExt = ".tmpl"
+
+// TemplateData is what config templates are executed with.
+type TemplateData struct {
+	User      string
+	Home      string
+	Hostname  string
+	Arch      string
+	OSVersion string
+	Env       map[string]string
+}
+
+// NewTemplateData returns the variables of a template loaded on h for the
+// user whose home is home, the current user's when empty.
+func NewTemplateData(h Host, home string) TemplateData {
+	d := TemplateData{
+		User:      h.User,
+		Hostname:  h.Hostname,
+		Arch:      runtime.GOARCH,
+		OSVersion: h.OSVersion,
+		Home:      home,
+		Env:       map[string]string{},
+	}
+	if d.Home == "" {
+		d.Home, _ = os.UserHomeDir()
+	}
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			d.Env[k] = v
+		}
+	}
+	return d
+}
+
+// IsTemplate reports whether the config file name is a template.
+func IsTemplate(name string) bool {
+	if remote.IsURL(name) {
+		if u, err := url.Parse(name); err == nil {
+			name = u.Path
+		}
+	}
+	return strings.HasSuffix(name, TemplateExt)
+}
+
+// Render executes the template data of the file name with vars.
+func Render(name string, data []byte, vars TemplateData) ([]byte, error) {
+	t, err := template.New(name).Option("missingkey=zero").Funcs(templateFuncs(vars)).Parse(string(data))
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := t.Execute(&out, vars); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// RenderFile reads file and renders it when it is a template.
+func RenderFile(file string, opts LoadOptions) ([]byte, error) {
+	return opts.Read.rendering(templateVars(opts.Vars, opts.Host, opts.Home)).read(file)
+}
+
+func templateFuncs(vars TemplateData) template.FuncMap {
+	return template.FuncMap{
+		// exists reports whether a file or directory exists, expanding "~".
+		"exists": func(path string) bool {
+			_, err := os.Stat(ExpandPath(path, vars.Home))
+			return err == nil
+		},
+		// default returns value, or def when value is empty.
+		"default": func(def, value any) any {
+			if value == nil || reflect.ValueOf(value).IsZero() {
+				return def
+			}
+			return value
+		},
+	}
+}
+
+// rendering returns r with template files rendered. vars is only called once
+// a template is read.
+func (r Reader) rendering(vars func() TemplateData) Reader {
+	return func(name string) ([]byte, error) {
+		data, err := r.read(name)
+		if err != nil || !IsTemplate(name) {
+			return data, err
+		}
+		out, err := Render(name, data, vars())
+		if err != nil {
+			return nil, fmt.Errorf("unable to render template: %w", err)
+		}
+		return out, nil
+	}
+}
+
+// templateVars returns vars, or the variables of host and home, detecting the
+// host when nil.
+func templateVars(vars *TemplateData, host *Host, home string) func() TemplateData {
+	return sync.OnceValue(func() TemplateData {
+		if vars != nil {
+			return *vars
+		}
+		if host == nil {
+			detected := DetectHost(runner.Exec{})
+			host = &detected
+		}
+		return NewTemplateData(*host, home)
+	})
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Render(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	if err := os.Mkdir(filepath.Join(home, "Projects"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	vars := TemplateData{
+		User:      "alice",
+		Home:      home,
+		Hostname:  "work-mbp",
+		Arch:      "arm64",
+		OSVersion: "15.1",
+		Env:       map[string]string{"EDITOR": "/Applications/Zed.app"},
+	}
+
+	tests := map[string]struct {
+		tmpl    string
+		want    string
+		wantErr string
+	}{
+		"variables": {
+			tmpl: "{{ .User }} {{ .Hostname }} {{ .Arch }} {{ .OSVersion }} {{ .Env.EDITOR }}",
+			want: "alice work-mbp arm64 15.1 /Applications/Zed.app",
+		},
+		"home": {
+			tmpl: "{{ .Home }}/Projects/{{ .User }}",
+			want: home + "/Projects/alice",
+		},
+		"condition": {
+			tmpl: "{{ if eq .Arch \"arm64\" }}- /Applications/Xcode.app{{ end }}",
+			want: "- /Applications/Xcode.app",
+		},
+		"exists": {
+			tmpl: "{{ exists \"~/Projects\" }} {{ exists \"~/Missing\" }}",
+			want: "true false",
+		},
+		"default": {
+			tmpl: "{{ .Env.TERMINAL | default \"/System/Applications/Utilities/Terminal.app\" }} {{ default \"vim\" .Env.EDITOR }}",
+			want: "/System/Applications/Utilities/Terminal.app /Applications/Zed.app",
+		},
+		"no template": {
+			tmpl: "dock_items: {apps: [/A.app]}\n",
+			want: "dock_items: {apps: [/A.app]}\n",
+		},
+		"parse error": {
+			tmpl:    "{{ if .User }}",
+			wantErr: "dorg.yml.tmpl:1:",
+		},
+		"unknown variable": {
+			tmpl:    "{{ .Shell }}",
+			wantErr: "can't evaluate field Shell",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Render("dorg.yml.tmpl", []byte(tc.tmpl), vars)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("Render error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render error: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("Render = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_IsTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		name string
+		want bool
+	}{
+		"template":       {name: "dorg.yml.tmpl", want: true},
+		"yaml":           {name: "dorg.yml", want: false},
+		"url":            {name: "https://example.com/dorg.yml.tmpl?token=abc", want: true},
+		"url query only": {name: "https://example.com/dorg.yml?f=.tmpl", want: false},
+		"stdin":          {name: "-", want: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsTemplate(tc.name); got != tc.want {
+				t.Fatalf("IsTemplate(%s) = %v, want %v", tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_Load_Template(t *testing.T) {
+	t.Parallel()
+
+	vars := &TemplateData{User: "alice", Home: "/Users/alice", Arch: "arm64"}
+	tests := map[string]struct {
+		files   map[string]string
+		want    Config
+		wantErr string
+	}{
+		"rendered": {
+			files: map[string]string{
+				"dorg.yml.tmpl": "dock_items:\n  apps:\n    - /A.app\n{{- if eq .Arch \"arm64\" }}\n    - /Applications/Xcode.app\n{{- end }}\n  others:\n    - path: \"{{ .Home }}/Projects/{{ .User }}\"\n",
+			},
+			want: Config{Dock: Dock{
+				Apps:   appList("/A.app", "/Applications/Xcode.app"),
+				Others: []Folder{{Path: "/Users/alice/Projects/alice"}},
+			}},
+		},
+		"included template": {
+			files: map[string]string{
+				"dorg.yml.tmpl":   "include: [shared.yml.tmpl]\ndock_items: {apps: {append: [/B.app]}}\n",
+				"shared.yml.tmpl": "dock_items: {apps: [/{{ .User }}.app]}\n",
+			},
+			want: Config{Dock: Dock{Apps: appList("/alice.app", "/B.app")}},
+		},
+		"plain yaml is not rendered": {
+			files: map[string]string{"dorg.yml.tmpl": "include: plain.yml\n", "plain.yml": "dock_items: {apps: [\"/{{ .User }}.app\"]}\n"},
+			want:  Config{Dock: Dock{Apps: appList("/{{ .User }}.app")}},
+		},
+		"render error": {
+			files:   map[string]string{"dorg.yml.tmpl": "dock_items: {apps: [{{ .Shell }}]}\n"},
+			wantErr: "unable to render template",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			for file, content := range tc.files {
+				if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+					t.Fatalf("failed to write %s: %v", file, err)
+				}
+			}
+
+			got, err := Load(filepath.Join(dir, "dorg.yml.tmpl"), LoadOptions{Host: &Host{}, Vars: vars})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("Load error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Load = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
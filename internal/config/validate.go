@@ -0,0 +1,492 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/5ouma/dorg/internal/apps"
+	yaml "gopkg.in/yaml.v3"
+)
+
+type Problem struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (p Problem) Error() string {
+	// TOML files have no positions.
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", p.File, p.Line, p.Column, p.Message)
+}
+
+type Problems []Problem
+
+func (p Problems) Error() string {
+	lines := make([]string, len(p))
+	for i, problem := range p {
+		lines[i] = problem.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+type ValidateOptions struct {
+	// Home is the directory "~" expands to when checking that apps exist, and
+	// the one templates see.
+	Home string
+	// SkipApps disables checking that listed apps are installed.
+	SkipApps bool
+	// Read reads the config file and the files it includes.
+	Read Reader
+	// Vars are the variables of templates. They are detected when nil.
+	Vars *TemplateData
+	// Format is the format of the config file, see LoadOptions.
+	Format Format
+}
+
+// Validate checks a config file against the schema dorg understands and
+// reports every problem with its position in the file, or in the output of a
+// template.
+func Validate(file string, opts ValidateOptions) (Problems, error) {
+	opts.Read = opts.Read.rendering(templateVars(opts.Vars, nil, opts.Home))
+	data, err := opts.Read.read(file)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateBytes(file, data, opts), nil
+}
+
+func ValidateBytes(file string, data []byte, opts ValidateOptions) Problems {
+	v := &validator{file: file, format: formatFor(file, opts.Format), opts: opts}
+	if id, err := includeID(file); err == nil {
+		v.stack = []string{id}
+	}
+	return v.validate(data)
+}
+
+func (v *validator) validate(data []byte) Problems {
+	file := v.file
+
+	doc, err := Decode(v.format, data)
+	if err != nil {
+		v.problems = append(v.problems, Problem{File: file, Line: 1, Column: 1, Message: err.Error()})
+		return v.problems
+	}
+	if len(doc.Content) == 0 {
+		v.problems = append(v.problems, Problem{File: file, Line: 1, Column: 1, Message: "config file is empty"})
+		return v.problems
+	}
+
+	root := rootRule
+	if hasKey(doc.Content[0], includeKey) {
+		root = includingRule
+	}
+	v.walk(root, doc.Content[0])
+	slices.SortStableFunc(v.problems, func(a, b Problem) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+	return append(v.problems, v.included...)
+}
+
+type rule struct {
+	kind  yaml.Kind
+	keys  map[string]*rule
+	items *rule
+	// values is the rule for the keys of a mapping that are not in keys.
+	values *rule
+	check  func(v *validator, n *yaml.Node)
+	// or is checked instead when a node is not of kind.
+	or *rule
+}
+
+var (
+	boolRule = &rule{kind: yaml.ScalarNode, check: func(v *validator, n *yaml.Node) {
+		if n.Tag != "!!bool" {
+			v.addf(n, "'%s' is not a boolean", n.Value)
+		}
+	}}
+	stringRule = &rule{kind: yaml.ScalarNode}
+	sizeRule   = &rule{kind: yaml.ScalarNode, check: numberIn(16, 128)}
+	// durationRule checks the autohide delay and animation speed, in seconds and as a factor.
+	durationRule = &rule{kind: yaml.ScalarNode, check: numberIn(0, math.Inf(1))}
+
+	spacerRule = &rule{kind: yaml.MappingNode, keys: map[string]*rule{
+		"spacer":      {kind: yaml.ScalarNode, check: oneOf(Spacers)},
+		"flex-spacer": boolRule,
+	}, check: func(v *validator, n *yaml.Node) {
+		if len(n.Content) != 2 {
+			v.addf(n, "expected exactly one of 'spacer' or 'flex-spacer'")
+		}
+	}}
+
+	hotCornerRule = &rule{kind: yaml.MappingNode, keys: map[string]*rule{
+		"action":    {kind: yaml.ScalarNode, check: enumIn(cornerActionNames)},
+		"modifiers": {kind: yaml.SequenceNode, items: &rule{kind: yaml.ScalarNode, check: enumIn(modifierNames)}},
+	}, check: requires("action")}
+
+	ensureRule = &rule{kind: yaml.MappingNode, keys: map[string]*rule{
+		"app":      {kind: yaml.ScalarNode, check: (*validator).checkApp},
+		"after":    stringRule,
+		"position": {kind: yaml.ScalarNode, check: oneOf(Positions)},
+	}, check: func(v *validator, n *yaml.Node) {
+		requires("app")(v, n)
+		if hasKey(n, "after") && hasKey(n, "position") {
+			v.addf(n, "expected at most one of 'after' or 'position'")
+		}
+	}}
+
+	includeRule   = &rule{kind: yaml.ScalarNode, check: (*validator).checkInclude}
+	includingRule *rule
+
+	rootRule = &rule{kind: yaml.MappingNode, keys: map[string]*rule{
+		"mode": {kind: yaml.ScalarNode, check: oneOf(Modes)},
+		"dock_items": {kind: yaml.MappingNode, keys: map[string]*rule{
+			"apps":    {kind: yaml.SequenceNode, items: &rule{kind: yaml.ScalarNode, check: (*validator).checkApp, or: spacerRule}},
+			"recents": {kind: yaml.SequenceNode, items: &rule{kind: yaml.ScalarNode, check: (*validator).checkApp}},
+			"present": {kind: yaml.SequenceNode, items: &rule{kind: yaml.ScalarNode, check: (*validator).checkApp, or: ensureRule}},
+			"absent":  {kind: yaml.SequenceNode, items: stringRule},
+			"others": {kind: yaml.SequenceNode, items: &rule{kind: yaml.MappingNode, keys: map[string]*rule{
+				"path":        {kind: yaml.ScalarNode, check: (*validator).checkFolderPath},
+				"file":        {kind: yaml.ScalarNode, check: (*validator).checkFolderPath},
+				"url":         {kind: yaml.ScalarNode, check: (*validator).checkURL},
+				"label":       stringRule,
+				"spacer":      {kind: yaml.ScalarNode, check: oneOf(Spacers)},
+				"flex-spacer": boolRule,
+				"sort":        {kind: yaml.ScalarNode, check: enumIn(sortNames)},
+				"display":     {kind: yaml.ScalarNode, check: enumIn(displayNames)},
+				"view":        {kind: yaml.ScalarNode, check: enumIn(viewNames)},
+			}, check: (*validator).checkOther}},
+			"settings": {kind: yaml.MappingNode, keys: map[string]*rule{
+				"tilesize":                sizeRule,
+				"largesize":               sizeRule,
+				"magnification":           boolRule,
+				"minimize-to-application": boolRule,
+				"autohide":                boolRule,
+				"show-recents":            boolRule,
+				"size-immutable":          boolRule,
+
+				"orientation":                       {kind: yaml.ScalarNode, check: oneOf(Orientations)},
+				"mineffect":                         {kind: yaml.ScalarNode, check: oneOf(MinEffects)},
+				"launchanim":                        boolRule,
+				"autohide-delay":                    durationRule,
+				"autohide-time-modifier":            durationRule,
+				"show-process-indicators":           boolRule,
+				"static-only":                       boolRule,
+				"mru-spaces":                        boolRule,
+				"showhidden":                        boolRule,
+				"scroll-to-open":                    boolRule,
+				"contents-immutable":                boolRule,
+				"position-immutable":                boolRule,
+				"magnify-immutable":                 boolRule,
+				"magsize-immutable":                 boolRule,
+				"autohide-immutable":                boolRule,
+				"mineffect-immutable":               boolRule,
+				"launchanim-immutable":              boolRule,
+				"show-process-indicators-immutable": boolRule,
+				"min-in-place-immutable":            boolRule,
+			}},
+		}},
+		"hot_corners": {kind: yaml.MappingNode, keys: map[string]*rule{
+			"top-left":     hotCornerRule,
+			"top-right":    hotCornerRule,
+			"bottom-left":  hotCornerRule,
+			"bottom-right": hotCornerRule,
+		}},
+		"target": {kind: yaml.MappingNode, keys: map[string]*rule{
+			"home":    stringRule,
+			"plist":   stringRule,
+			"offline": boolRule,
+		}},
+	}}
+)
+
+// Profiles and overlays take the same keys as the rest of the config, except
+// that lists may also be edited with ListOps.
+func init() {
+	body := mergeRule(rootRule)
+	rootRule.keys[profilesKey] = &rule{kind: yaml.MappingNode, values: body}
+
+	overlay := *body
+	overlay.keys = maps.Clone(body.keys)
+	overlay.keys[matchKey] = &rule{kind: yaml.MappingNode, keys: map[string]*rule{
+		"hostname": {kind: yaml.ScalarNode, check: checkPattern},
+		"model":    {kind: yaml.ScalarNode, check: checkPattern},
+		"os":       {kind: yaml.ScalarNode, check: checkPattern},
+		"user":     {kind: yaml.ScalarNode, check: checkPattern},
+	}, check: func(v *validator, n *yaml.Node) {
+		if len(n.Content) == 0 {
+			v.addf(n, "expected at least one of 'hostname', 'model', 'os' or 'user'")
+		}
+	}}
+	overlay.check = requires(matchKey)
+	rootRule.keys[overlaysKey] = &rule{kind: yaml.SequenceNode, items: &overlay}
+	rootRule.keys[includeKey] = &rule{kind: yaml.SequenceNode, items: includeRule, or: includeRule}
+
+	// The sections of a file that includes others are merged onto them.
+	including := *body
+	including.keys = maps.Clone(body.keys)
+	for _, key := range []string{profilesKey, overlaysKey, includeKey} {
+		including.keys[key] = rootRule.keys[key]
+	}
+	includingRule = &including
+}
+
+// mergeRule returns a copy of r in which every list may also be a mapping of
+// ListOps. The items to remove only have to be of the right kind, and
+// mappings are not checked as a whole since they are merged key by key.
+func mergeRule(r *rule) *rule {
+	out := *r
+	if r.kind == yaml.MappingNode {
+		out.check = nil
+	}
+	if r.keys != nil {
+		out.keys = make(map[string]*rule, len(r.keys))
+		for k, child := range r.keys {
+			out.keys[k] = mergeRule(child)
+		}
+	}
+	if r.kind == yaml.SequenceNode {
+		ops := &rule{kind: yaml.MappingNode, keys: map[string]*rule{}}
+		for _, op := range ListOps {
+			ops.keys[op] = r
+		}
+		ops.keys["remove"] = &rule{kind: yaml.SequenceNode, items: &rule{kind: r.items.kind, or: r.items.or}}
+		out.or = ops
+	}
+	return &out
+}
+
+func checkPattern(v *validator, n *yaml.Node) {
+	if _, err := path.Match(n.Value, ""); err != nil {
+		v.addf(n, "'%s' is not a valid pattern", n.Value)
+	}
+}
+
+var kindNames = map[yaml.Kind]string{
+	yaml.MappingNode:  "a mapping",
+	yaml.SequenceNode: "a list",
+	yaml.ScalarNode:   "a single value",
+}
+
+type validator struct {
+	file     string
+	format   Format
+	opts     ValidateOptions
+	resolver *apps.Resolver
+	problems Problems
+	// stack holds the absolute paths of the files being validated, and
+	// included the problems found in the files they include.
+	stack    []string
+	included Problems
+}
+
+func (v *validator) addf(n *yaml.Node, format string, args ...any) {
+	v.problems = append(v.problems, Problem{File: v.file, Line: n.Line, Column: n.Column, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) walk(r *rule, n *yaml.Node) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return
+	}
+	if n.Kind != r.kind && r.or != nil && n.Kind == r.or.kind {
+		r = r.or
+	}
+	if n.Kind != r.kind {
+		v.addf(n, "expected %s", kindNames[r.kind])
+		return
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		seen := map[string]bool{}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if seen[key.Value] {
+				v.addf(key, "duplicate key '%s'", key.Value)
+				continue
+			}
+			seen[key.Value] = true
+
+			child, ok := r.keys[key.Value]
+			if !ok && r.values != nil {
+				child, ok = r.values, true
+			}
+			if !ok {
+				v.addf(key, "unknown key '%s'", key.Value)
+				continue
+			}
+			v.walk(child, value)
+		}
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			v.walk(r.items, item)
+		}
+	}
+
+	if r.check != nil {
+		r.check(v, n)
+	}
+}
+
+// enumIn accepts the names and values of an enum, and 0 for unset, which
+// integer configs have always been able to use.
+func enumIn[T ~int](names map[T]string) func(*validator, *yaml.Node) {
+	return func(v *validator, n *yaml.Node) {
+		var value T
+		if err := parseEnum(n, names, &value); err != nil {
+			v.addf(n, "'%s' must be one of %s", n.Value, enumList(names))
+			return
+		}
+		if _, ok := names[value]; !ok && value != 0 {
+			v.addf(n, "%s must be one of %s", n.Value, enumList(names))
+		}
+	}
+}
+
+func numberIn(minimum, maximum float64) func(*validator, *yaml.Node) {
+	return func(v *validator, n *yaml.Node) {
+		f, err := strconv.ParseFloat(n.Value, 64)
+		if (n.Tag != "!!int" && n.Tag != "!!float") || err != nil {
+			v.addf(n, "'%s' is not a number", n.Value)
+			return
+		}
+		if f < minimum || f > maximum {
+			if math.IsInf(maximum, 1) {
+				v.addf(n, "%s must be at least %g", n.Value, minimum)
+				return
+			}
+			v.addf(n, "%s must be between %g and %g", n.Value, minimum, maximum)
+		}
+	}
+}
+
+func requires(key string) func(*validator, *yaml.Node) {
+	return func(v *validator, n *yaml.Node) {
+		if !hasKey(n, key) {
+			v.addf(n, "missing key '%s'", key)
+		}
+	}
+}
+
+func hasKey(n *yaml.Node, key string) bool {
+	for i := 0; i < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return true
+		}
+	}
+	return false
+}
+
+func oneOf(choices []string) func(*validator, *yaml.Node) {
+	return func(v *validator, n *yaml.Node) {
+		if !slices.Contains(choices, n.Value) {
+			v.addf(n, "'%s' must be one of %s", n.Value, strings.Join(choices, ", "))
+		}
+	}
+}
+
+func (v *validator) checkFolderPath(n *yaml.Node) {
+	if n.Value != "~" && !strings.HasPrefix(n.Value, "~/") && !filepath.IsAbs(ExpandPath(n.Value, v.opts.Home)) {
+		v.addf(n, "folder path '%s' must be absolute or start with '~/'", n.Value)
+	}
+}
+
+// checkOther makes sure an others item is exactly one kind of tile and only
+// uses the keys that kind understands.
+func (v *validator) checkOther(n *yaml.Node) {
+	keys := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		keys[n.Content[i].Value] = n.Content[i]
+	}
+
+	var kinds []string
+	for _, kind := range []string{"path", "file", "url", "spacer", "flex-spacer"} {
+		if keys[kind] != nil {
+			kinds = append(kinds, kind)
+		}
+	}
+	if len(kinds) != 1 {
+		v.addf(n, "expected exactly one of 'path', 'file', 'url', 'spacer' or 'flex-spacer'")
+		return
+	}
+
+	for key, node := range keys {
+		switch key {
+		case "sort", "display", "view":
+			if kinds[0] != "path" {
+				v.addf(node, "'%s' only applies to folders", key)
+			}
+		case "label":
+			if kinds[0] != "url" {
+				v.addf(node, "'%s' only applies to URLs", key)
+			}
+		}
+	}
+}
+
+func (v *validator) checkURL(n *yaml.Node) {
+	u, err := url.Parse(n.Value)
+	if err != nil || u.Scheme == "" {
+		v.addf(n, "'%s' is not a URL", n.Value)
+	}
+}
+
+// checkInclude validates the included file too, reporting its problems
+// against that file.
+func (v *validator) checkInclude(n *yaml.Node) {
+	path := includePath(v.file, n.Value)
+	id, err := includeID(path)
+	if err != nil {
+		v.addf(n, "%v", err)
+		return
+	}
+	if i := slices.Index(v.stack, id); i >= 0 {
+		v.addf(n, "include cycle: %s", strings.Join(append(slices.Clone(v.stack[i:]), id), " -> "))
+		return
+	}
+
+	data, err := v.opts.Read.read(path)
+	if err != nil {
+		v.addf(n, "unable to include '%s': %v", n.Value, err)
+		return
+	}
+	inc := &validator{file: path, format: FormatOf(path), opts: v.opts, resolver: v.resolver, stack: append(slices.Clone(v.stack), id)}
+	v.included = append(v.included, inc.validate(data)...)
+}
+
+func (v *validator) checkApp(n *yaml.Node) {
+	if v.opts.SkipApps || strings.TrimSpace(n.Value) == "" {
+		return
+	}
+
+	if !apps.IsPath(n.Value) {
+		if v.resolver == nil {
+			v.resolver = apps.NewResolver(v.opts.Home)
+		}
+		if _, err := v.resolver.Resolve(n.Value); err != nil {
+			v.addf(n, "%v", err)
+		}
+		return
+	}
+
+	if _, err := os.Stat(ExpandPath(n.Value, v.opts.Home)); err != nil {
+		v.addf(n, "app '%s' does not exist", n.Value)
+	}
+}
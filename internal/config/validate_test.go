@@ -0,0 +1,224 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_ValidateBytes(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	app := filepath.Join(home, "Applications", "Calculator.app")
+	if err := os.MkdirAll(app, 0755); err != nil {
+		t.Fatalf("failed to create app: %v", err)
+	}
+
+	tests := map[string]struct {
+		content  string
+		skipApps bool
+		want     []string
+	}{
+		"valid": {
+			content: `dock_items:
+  apps:
+    - ` + app + `
+    - ~/Applications/Calculator.app
+    - ""
+  others:
+    - path: ~/Downloads
+      sort: 1
+      display: 0
+      view: 3
+  settings:
+    tilesize: 35
+    largesize: 64.5
+    autohide: true
+target:
+  offline: true
+`,
+			want: nil,
+		},
+		"unknown keys": {
+			content: "dock_items:\n  apps: []\n  folders: []\nextra: 1\n",
+			want:    []string{"3:3: unknown key 'folders'", "4:1: unknown key 'extra'"},
+		},
+		"out of range sizes": {
+			content: "dock_items:\n  settings:\n    tilesize: 8\n    largesize: 200\n",
+			want:    []string{"3:15: 8 must be between 16 and 128", "4:16: 200 must be between 16 and 128"},
+		},
+		"tile size not a number": {
+			content: "dock_items:\n  settings:\n    tilesize: big\n",
+			want:    []string{"3:15: 'big' is not a number"},
+		},
+		"optional settings": {
+			content: "dock_items:\n  settings:\n    orientation: left\n    mineffect: scale\n    autohide-delay: 0\n    autohide-time-modifier: 0.5\n    mru-spaces: false\n    contents-immutable: true\n",
+		},
+		"invalid optional settings": {
+			content: "dock_items:\n  settings:\n    orientation: top\n    mineffect: fade\n    autohide-delay: -1\n    static-only: maybe\n",
+			want:    []string{"3:18: 'top' must be one of bottom, left, right", "4:16: 'fade' must be one of genie, scale, suck", "5:21: -1 must be at least 0", "6:18: 'maybe' is not a boolean"},
+		},
+		"hot corners": {
+			content: "hot_corners:\n  top-left:\n    action: mission-control\n    modifiers: [command]\n  bottom-right:\n    action: 14\n",
+		},
+		"invalid hot corners": {
+			content: "hot_corners:\n  middle:\n    action: desktop\n  top-left:\n    action: explode\n    modifiers: [hyper]\n  top-right:\n    modifiers: [shift]\n",
+			want:    []string{"2:3: unknown key 'middle'", "5:13: 'explode' must be one of none, mission-control", "6:17: 'hyper' must be one of shift, control, option, command", "8:5: missing key 'action'"},
+		},
+		"invalid enums": {
+			content: "dock_items:\n  others:\n    - path: ~/Downloads\n      sort: 9\n      display: 2\n      view: x\n",
+			want:    []string{"4:13: 9 must be one of name, date-added", "5:16: 2 must be one of stack, folder", "6:13: 'x' must be one of automatic, fan, grid, list"},
+		},
+		"unset sort": {
+			content: "dock_items:\n  others:\n    - path: ~/Downloads\n      sort: 0\n",
+		},
+		"folder enum names": {
+			content: "dock_items:\n  others:\n    - path: ~/Downloads\n      sort: date-added\n      display: Folder\n      view: 3\n",
+		},
+		"spacers": {
+			content: "dock_items:\n  apps:\n    - {spacer: small}\n    - {flex-spacer: true}\n    - \"\"\n  others:\n    - spacer: large\n",
+		},
+		"invalid spacers": {
+			content: "dock_items:\n  apps:\n    - {spacer: huge}\n    - {spacer: small, flex-spacer: true}\n    - {path: /A.app}\n  others:\n    - spacer: small\n      sort: name\n",
+			want: []string{
+				"3:16: 'huge' must be one of small, large",
+				"4:7: expected exactly one of 'spacer' or 'flex-spacer'",
+				"5:8: unknown key 'path'",
+				"8:7: 'sort' only applies to folders",
+			},
+		},
+		"file and url tiles": {
+			content: "dock_items:\n  others:\n    - file: ~/Documents/notes.txt\n    - url: smb://server/share\n      label: Share\n",
+		},
+		"invalid others tiles": {
+			content: "dock_items:\n  others:\n    - path: ~/Downloads\n      url: https://example.com\n    - url: intranet\n      sort: name\n    - file: ~/notes.txt\n      label: Notes\n    - label: Nothing\n",
+			want: []string{
+				"3:7: expected exactly one of 'path', 'file', 'url', 'spacer' or 'flex-spacer'",
+				"5:12: 'intranet' is not a URL",
+				"6:7: 'sort' only applies to folders",
+				"8:7: 'label' only applies to URLs",
+				"9:7: expected exactly one of 'path', 'file', 'url', 'spacer' or 'flex-spacer'",
+			},
+		},
+		"absolute and env folders": {
+			content: "dock_items:\n  others:\n    - path: /Users/Shared\n    - path: $HOME/Downloads\n",
+		},
+		"relative folder": {
+			content: "dock_items:\n  others:\n    - path: Downloads\n",
+			want:    []string{"3:13: folder path 'Downloads' must be absolute or start with '~/'"},
+		},
+		"missing apps": {
+			content: "dock_items:\n  apps:\n    - /Applications/Missing.app\n    - com.example.Missing\n",
+			want:    []string{"3:7: app '/Applications/Missing.app' does not exist", "4:7: app not found: 'com.example.Missing'"},
+		},
+		"missing apps skipped": {
+			content:  "dock_items:\n  apps:\n    - /Applications/Missing.app\n",
+			skipApps: true,
+			want:     nil,
+		},
+		"ensure mode": {
+			content:  "mode: ensure\ndock_items:\n  present:\n    - /A.app\n    - {app: /B.app, after: Safari}\n    - {app: /C.app, position: first}\n  absent:\n    - Chess\n",
+			skipApps: true,
+		},
+		"invalid ensure mode": {
+			content:  "mode: merge\ndock_items:\n  present:\n    - {after: Safari}\n    - {app: /B.app, after: Safari, position: last}\n    - {app: /C.app, position: middle}\n",
+			skipApps: true,
+			want: []string{
+				"1:7: 'merge' must be one of exact, ensure",
+				"4:7: missing key 'app'",
+				"5:7: expected at most one of 'after' or 'position'",
+				"6:31: 'middle' must be one of first, last",
+			},
+		},
+		"profiles and overlays": {
+			content:  "dock_items: {apps: [/A.app]}\nprofiles:\n  dev:\n    dock_items: {apps: {append: [/B.app], remove: [/Missing.app]}}\n    hot_corners: {top-left: {modifiers: [command]}}\noverlays:\n  - match: {hostname: 'kiosk-*', os: '14.*'}\n    dock_items: {apps: [/C.app]}\n",
+			skipApps: true,
+		},
+		"invalid profiles and overlays": {
+			content:  "profiles:\n  dev:\n    dock_items: {apps: {insert: [/B.app]}}\noverlays:\n  - dock_items: {}\n  - match: {}\n  - match: {user: '[a', serial: x}\n",
+			skipApps: true,
+			want: []string{
+				"3:25: unknown key 'insert'",
+				"5:5: missing key 'match'",
+				"6:12: expected at least one of 'hostname', 'model', 'os' or 'user'",
+				"7:19: '[a' is not a valid pattern",
+				"7:25: unknown key 'serial'",
+			},
+		},
+		"wrong kinds": {
+			content: "dock_items:\n  apps: /Applications/Safari.app\n  settings:\n    autohide: yes please\n",
+			want:    []string{"2:9: expected a list", "4:15: 'yes please' is not a boolean"},
+		},
+		"duplicate key": {
+			content: "dock_items:\n  apps: []\n  apps: []\n",
+			want:    []string{"3:3: duplicate key 'apps'"},
+		},
+		"invalid yaml": {
+			content: "dock_items: [unclosed",
+			want:    []string{"1:1: yaml:"},
+		},
+		"empty": {
+			content: "",
+			want:    []string{"1:1: config file is empty"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			problems := ValidateBytes("dorg.yml", []byte(tc.content), ValidateOptions{Home: home, SkipApps: tc.skipApps})
+			if len(problems) != len(tc.want) {
+				t.Fatalf("got %d problems, want %d:\n%v", len(problems), len(tc.want), problems)
+			}
+			for i, want := range tc.want {
+				if !strings.HasPrefix(problems[i].Error(), "dorg.yml:"+want) {
+					t.Fatalf("problem %d = %s, want prefix dorg.yml:%s", i, problems[i].Error(), want)
+				}
+			}
+		})
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Validate(filepath.Join(t.TempDir(), "missing.yml"), ValidateOptions{}); err == nil {
+		t.Fatalf("expected error for missing file")
+	}
+}
+
+func Test_Validate_Include(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"dorg.yml":    "include: [company.yml, cycle.yml, missing.yml]\ndock_items: {apps: {append: [/C.app]}}\n",
+		"company.yml": "dock_items:\n  others: [{path: Downloads}]\n",
+		"cycle.yml":   "include: dorg.yml\n",
+	}
+	for file, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", file, err)
+		}
+	}
+
+	problems, err := Validate(filepath.Join(dir, "dorg.yml"), ValidateOptions{SkipApps: true})
+	if err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+	want := []string{
+		"dorg.yml:1:35: unable to include 'missing.yml'",
+		"company.yml:2:19: folder path 'Downloads' must be absolute or start with '~/'",
+		"cycle.yml:1:10: include cycle: ",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("got %d problems %v, want %d", len(problems), problems, len(want))
+	}
+	for i, w := range want {
+		if got := strings.TrimPrefix(problems[i].Error(), dir+string(filepath.Separator)); !strings.HasPrefix(got, w) {
+			t.Fatalf("problem %d = %s, want prefix %s", i, got, w)
+		}
+	}
+}
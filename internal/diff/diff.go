@@ -0,0 +1,267 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/5ouma/dorg/internal/config"
+)
+
+type Kind string
+
+const (
+	Added    Kind = "added"
+	Removed  Kind = "removed"
+	Moved    Kind = "moved"
+	Modified Kind = "modified"
+)
+
+const (
+	SectionApps       = "apps"
+	SectionOthers     = "others"
+	SectionRecents    = "recents"
+	SectionSettings   = "settings"
+	SectionHotCorners = "hot_corners"
+)
+
+type Change struct {
+	Section   string `json:"section"`
+	Kind      Kind   `json:"kind"`
+	Key       string `json:"key"`
+	Field     string `json:"field,omitempty"`
+	From      any    `json:"from,omitempty"`
+	To        any    `json:"to,omitempty"`
+	FromIndex *int   `json:"from_index,omitempty"`
+	ToIndex   *int   `json:"to_index,omitempty"`
+}
+
+type Result struct {
+	Changes []Change `json:"changes"`
+}
+
+func (r Result) Empty() bool {
+	return len(r.Changes) == 0
+}
+
+func (r Result) Section(name string) []Change {
+	var out []Change
+	for _, c := range r.Changes {
+		if c.Section == name {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Compare reports the changes needed to turn from into to.
+func Compare(from, to config.Config) Result {
+	var r Result
+	r.Changes = append(r.Changes, compareApps(SectionApps, appKeys(from.Dock.Apps), appKeys(to.Dock.Apps))...)
+	r.Changes = append(r.Changes, compareOthers(from.Dock.Others, to.Dock.Others)...)
+	r.Changes = append(r.Changes, compareApps(SectionRecents, from.Dock.Recents, to.Dock.Recents)...)
+	r.Changes = append(r.Changes, compareSettings(from.Dock.Settings, to.Dock.Settings)...)
+	r.Changes = append(r.Changes, compareHotCorners(from.HotCorners, to.HotCorners)...)
+	return r
+}
+
+func compareApps(section string, from, to []string) []Change {
+	m := match(from, to)
+
+	var changes []Change
+	for _, i := range m.removed {
+		changes = append(changes, Change{Section: section, Kind: Removed, Key: from[i], FromIndex: index(i)})
+	}
+	for _, j := range m.added {
+		changes = append(changes, Change{Section: section, Kind: Added, Key: to[j], ToIndex: index(j)})
+	}
+	for _, p := range m.moved {
+		changes = append(changes, Change{Section: section, Kind: Moved, Key: to[p.to], FromIndex: index(p.from), ToIndex: index(p.to)})
+	}
+	return changes
+}
+
+func compareOthers(from, to []config.Folder) []Change {
+	m := match(folderKeys(from), folderKeys(to))
+
+	var changes []Change
+	for _, i := range m.removed {
+		changes = append(changes, Change{Section: SectionOthers, Kind: Removed, Key: from[i].Key(), FromIndex: index(i)})
+	}
+	for _, j := range m.added {
+		changes = append(changes, Change{Section: SectionOthers, Kind: Added, Key: to[j].Key(), ToIndex: index(j)})
+	}
+	for _, p := range m.moved {
+		changes = append(changes, Change{Section: SectionOthers, Kind: Moved, Key: to[p.to].Key(), FromIndex: index(p.from), ToIndex: index(p.to)})
+	}
+	for _, p := range append(m.kept, m.moved...) {
+		changes = append(changes, compareFields(SectionOthers, to[p.to].Key(), from[p.from], to[p.to])...)
+	}
+	return changes
+}
+
+func compareSettings(from, to *config.DockSettings) []Change {
+	if from == nil {
+		from = &config.DockSettings{}
+	}
+	if to == nil {
+		to = &config.DockSettings{}
+	}
+
+	var changes []Change
+	for _, c := range compareFields(SectionSettings, "", *from, *to) {
+		c.Key, c.Field = c.Field, ""
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+func compareHotCorners(from, to config.HotCorners) []Change {
+	var changes []Change
+	for _, name := range config.Corners {
+		a, inFrom := from[name]
+		b, inTo := to[name]
+		switch {
+		case inFrom && !inTo:
+			changes = append(changes, Change{Section: SectionHotCorners, Kind: Removed, Key: name})
+		case !inFrom && inTo:
+			changes = append(changes, Change{Section: SectionHotCorners, Kind: Added, Key: name})
+		case inFrom && inTo:
+			changes = append(changes, compareFields(SectionHotCorners, name, a, b)...)
+		}
+	}
+	return changes
+}
+
+// compareFields reports every yaml-tagged field whose value differs between from and to.
+func compareFields(section, key string, from, to any) []Change {
+	fromFields, toFields := fields(from), fields(to)
+
+	var changes []Change
+	for i, f := range fromFields {
+		a, b := f.value, toFields[i].value
+		if fmt.Sprint(a) == fmt.Sprint(b) {
+			continue
+		}
+		changes = append(changes, Change{Section: section, Kind: Modified, Key: key, Field: f.name, From: a, To: b})
+	}
+	return changes
+}
+
+type field struct {
+	name  string
+	value any
+}
+
+// fields lists the yaml-tagged fields of a struct other than its identifying path.
+func fields(v any) []field {
+	rv := reflect.ValueOf(v)
+
+	var out []field
+	for i := range rv.NumField() {
+		name := fieldName(rv.Type().Field(i))
+		if name == "" || name == "path" {
+			continue
+		}
+		out = append(out, field{name: name, value: value(rv.Field(i))})
+	}
+	return out
+}
+
+func fieldName(f reflect.StructField) string {
+	tag := f.Tag.Get("yaml")
+	if tag == "-" {
+		return ""
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return strings.ToLower(f.Name)
+	}
+	return name
+}
+
+func value(v reflect.Value) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+func index(i int) *int {
+	return &i
+}
+
+type pair struct {
+	from, to int
+}
+
+type matching struct {
+	kept    []pair
+	moved   []pair
+	removed []int
+	added   []int
+}
+
+// match aligns two keyed lists. Items on the longest common subsequence are
+// kept, items present on both sides but out of order are moved, and the rest
+// are removed or added.
+func match(from, to []string) matching {
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var m matching
+	fromUsed := make([]bool, len(from))
+	toUsed := make([]bool, len(to))
+	for i, j := 0, 0; i < len(from) && j < len(to); {
+		switch {
+		case from[i] == to[j]:
+			m.kept = append(m.kept, pair{i, j})
+			fromUsed[i], toUsed[j] = true, true
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	for j, key := range to {
+		if toUsed[j] {
+			continue
+		}
+		for i := range from {
+			if !fromUsed[i] && from[i] == key {
+				m.moved = append(m.moved, pair{i, j})
+				fromUsed[i], toUsed[j] = true, true
+				break
+			}
+		}
+	}
+
+	for i, used := range fromUsed {
+		if !used {
+			m.removed = append(m.removed, i)
+		}
+	}
+	for j, used := range toUsed {
+		if !used {
+			m.added = append(m.added, j)
+		}
+	}
+	return m
+}
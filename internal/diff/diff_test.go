@@ -0,0 +1,130 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/5ouma/dorg/internal/config"
+)
+
+func Test_Compare(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		from config.Config
+		to   config.Config
+		want []Change
+	}{
+		"equal": {
+			from: config.Config{Dock: config.Dock{Apps: []config.App{{Path: "/A.app"}, {Path: "/B.app"}}}},
+			to:   config.Config{Dock: config.Dock{Apps: []config.App{{Path: "/A.app"}, {Path: "/B.app"}}}},
+			want: nil,
+		},
+		"app added and removed": {
+			from: config.Config{Dock: config.Dock{Apps: []config.App{{Path: "/A.app"}, {Path: "/B.app"}}}},
+			to:   config.Config{Dock: config.Dock{Apps: []config.App{{Path: "/A.app"}, {Path: "/C.app"}}}},
+			want: []Change{
+				{Section: SectionApps, Kind: Removed, Key: "/B.app", FromIndex: index(1)},
+				{Section: SectionApps, Kind: Added, Key: "/C.app", ToIndex: index(1)},
+			},
+		},
+		"app moved": {
+			from: config.Config{Dock: config.Dock{Apps: []config.App{{Path: "/A.app"}, {Path: "/B.app"}, {Path: "/C.app"}}}},
+			to:   config.Config{Dock: config.Dock{Apps: []config.App{{Path: "/C.app"}, {Path: "/A.app"}, {Path: "/B.app"}}}},
+			want: []Change{
+				{Section: SectionApps, Kind: Moved, Key: "/C.app", FromIndex: index(2), ToIndex: index(0)},
+			},
+		},
+		"folder modified": {
+			from: config.Config{Dock: config.Dock{Others: []config.Folder{{Path: "~/Downloads", Sort: 1, View: 2}}}},
+			to:   config.Config{Dock: config.Dock{Others: []config.Folder{{Path: "~/Downloads", Sort: 2, View: 2}}}},
+			want: []Change{
+				{Section: SectionOthers, Kind: Modified, Key: "~/Downloads", Field: "sort", From: config.SortName, To: config.SortDateAdded},
+			},
+		},
+		"hot corners": {
+			from: config.Config{HotCorners: config.HotCorners{"top-left": {Action: config.ActionDesktop}, "bottom-left": {Action: config.ActionLaunchpad}}},
+			to:   config.Config{HotCorners: config.HotCorners{"top-left": {Action: config.ActionLockScreen}, "top-right": {Action: config.ActionQuickNote}}},
+			want: []Change{
+				{Section: SectionHotCorners, Kind: Modified, Key: "top-left", Field: "action", From: config.ActionDesktop, To: config.ActionLockScreen},
+				{Section: SectionHotCorners, Kind: Added, Key: "top-right"},
+				{Section: SectionHotCorners, Kind: Removed, Key: "bottom-left"},
+			},
+		},
+		"settings modified": {
+			from: config.Config{Dock: config.Dock{Settings: &config.DockSettings{TileSize: 32, AutoHide: true}}},
+			to:   config.Config{Dock: config.Dock{Settings: &config.DockSettings{TileSize: 32.0, AutoHide: false}}},
+			want: []Change{
+				{Section: SectionSettings, Kind: Modified, Key: "autohide", From: true, To: false},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Compare(tc.from, tc.to)
+			if len(got.Changes) != len(tc.want) {
+				t.Fatalf("got %d changes %+v, want %d", len(got.Changes), got.Changes, len(tc.want))
+			}
+			for i, c := range got.Changes {
+				if describe(c) != describe(tc.want[i]) {
+					t.Fatalf("change %d = %s, want %s", i, describe(c), describe(tc.want[i]))
+				}
+			}
+		})
+	}
+}
+
+func Test_match(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		from, to       []string
+		kept, moved    int
+		removed, added int
+	}{
+		"identical":  {from: []string{"a", "b"}, to: []string{"a", "b"}, kept: 2},
+		"duplicates": {from: []string{"", "a", ""}, to: []string{"a", "", ""}, kept: 2, moved: 1},
+		"disjoint":   {from: []string{"a"}, to: []string{"b"}, removed: 1, added: 1},
+		"empty":      {from: nil, to: []string{"a"}, added: 1},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m := match(tc.from, tc.to)
+			if len(m.kept) != tc.kept || len(m.moved) != tc.moved || len(m.removed) != tc.removed || len(m.added) != tc.added {
+				t.Fatalf("match = %+v", m)
+			}
+		})
+	}
+}
+
+func Test_Render(t *testing.T) {
+	t.Parallel()
+
+	r := Compare(
+		config.Config{Dock: config.Dock{Apps: []config.App{{Path: "/A.app"}}}},
+		config.Config{Dock: config.Dock{Apps: []config.App{{Path: "/B.app"}}}},
+	)
+	buf := new(bytes.Buffer)
+	Render(buf, r)
+	for _, want := range []string{"Apps", "/A.app", "/B.app"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("render output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func describe(c Change) string {
+	deref := func(i *int) any {
+		if i == nil {
+			return nil
+		}
+		return *i
+	}
+	return fmt.Sprintf("%s|%s|%s|%s|%v|%v|%v|%v", c.Section, c.Kind, c.Key, c.Field, c.From, c.To, deref(c.FromIndex), deref(c.ToIndex))
+}
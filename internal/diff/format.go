@@ -0,0 +1,256 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/5ouma/dorg/internal/config"
+)
+
+type Format string
+
+const (
+	FormatUnified    Format = "unified"
+	FormatJSON       Format = "json"
+	FormatSideBySide Format = "side-by-side"
+)
+
+var Formats = []Format{FormatUnified, FormatJSON, FormatSideBySide}
+
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown diff format %q: must be one of %v", s, Formats)
+}
+
+type Op byte
+
+const (
+	OpEqual   Op = ' '
+	OpRemove  Op = '-'
+	OpAdd     Op = '+'
+	OpChanged Op = '|'
+)
+
+// Row is one aligned line of a section, with the from side on the left and the to side on the right.
+type Row struct {
+	Op    Op
+	Left  string
+	Right string
+}
+
+type Hunk struct {
+	Section string
+	Rows    []Row
+}
+
+// Align pairs up the items of from and to section by section.
+func Align(from, to config.Config) []Hunk {
+	return []Hunk{
+		{Section: SectionApps, Rows: alignList(appKeys(from.Dock.Apps), appKeys(to.Dock.Apps), appKeys(from.Dock.Apps), appKeys(to.Dock.Apps))},
+		{Section: SectionOthers, Rows: alignList(folderKeys(from.Dock.Others), folderKeys(to.Dock.Others), folderLines(from.Dock.Others), folderLines(to.Dock.Others))},
+		{Section: SectionRecents, Rows: alignList(from.Dock.Recents, to.Dock.Recents, from.Dock.Recents, to.Dock.Recents)},
+		{Section: SectionSettings, Rows: alignSettings(from.Dock.Settings, to.Dock.Settings)},
+		{Section: SectionHotCorners, Rows: alignList(cornerKeys(from.HotCorners), cornerKeys(to.HotCorners), cornerLines(from.HotCorners), cornerLines(to.HotCorners))},
+	}
+}
+
+func alignList(fromKeys, toKeys, fromLines, toLines []string) []Row {
+	m := match(fromKeys, toKeys)
+	fromKept := make(map[int]bool, len(m.kept))
+	toKept := make(map[int]bool, len(m.kept))
+	for _, p := range m.kept {
+		fromKept[p.from], toKept[p.to] = true, true
+	}
+
+	var rows []Row
+	for i, j := 0, 0; i < len(fromKeys) || j < len(toKeys); {
+		switch {
+		case i < len(fromKeys) && !fromKept[i]:
+			rows = append(rows, Row{Op: OpRemove, Left: fromLines[i]})
+			i++
+		case j < len(toKeys) && !toKept[j]:
+			rows = append(rows, Row{Op: OpAdd, Right: toLines[j]})
+			j++
+		default:
+			op := OpEqual
+			if fromLines[i] != toLines[j] {
+				op = OpChanged
+			}
+			rows = append(rows, Row{Op: op, Left: fromLines[i], Right: toLines[j]})
+			i++
+			j++
+		}
+	}
+	return rows
+}
+
+func alignSettings(from, to *config.DockSettings) []Row {
+	if from == nil && to == nil {
+		return nil
+	}
+	fromLines, toLines := settingLines(from), settingLines(to)
+
+	rows := make([]Row, len(fromLines))
+	for i := range fromLines {
+		op := OpEqual
+		if fromLines[i] != toLines[i] {
+			op = OpChanged
+		}
+		rows[i] = Row{Op: op, Left: fromLines[i], Right: toLines[i]}
+	}
+	return rows
+}
+
+func settingLines(s *config.DockSettings) []string {
+	if s == nil {
+		s = &config.DockSettings{}
+	}
+	var lines []string
+	for _, f := range fields(*s) {
+		if f.value == nil {
+			lines = append(lines, fmt.Sprintf("%s: null", f.name))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %v", f.name, f.value))
+	}
+	return lines
+}
+
+func cornerKeys(corners config.HotCorners) []string {
+	var out []string
+	for _, name := range config.Corners {
+		if _, ok := corners[name]; ok {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+func cornerLines(corners config.HotCorners) []string {
+	var out []string
+	for _, name := range cornerKeys(corners) {
+		c := corners[name]
+		if len(c.Modifiers) == 0 {
+			out = append(out, fmt.Sprintf("%s: %s", name, c.Action))
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s: %s (%v)", name, c.Action, c.Modifiers))
+	}
+	return out
+}
+
+func appKeys(apps []config.App) []string {
+	out := make([]string, len(apps))
+	for i, app := range apps {
+		out[i] = app.String()
+	}
+	return out
+}
+
+func folderKeys(folders []config.Folder) []string {
+	out := make([]string, len(folders))
+	for i, f := range folders {
+		out[i] = f.Key()
+	}
+	return out
+}
+
+func folderLines(folders []config.Folder) []string {
+	out := make([]string, len(folders))
+	for i, f := range folders {
+		switch {
+		case f.URL != "":
+			out[i] = fmt.Sprintf("%s (label: %s)", f.URL, f.Label)
+		case f.File != "", f.Spacer != "", f.FlexSpacer:
+			out[i] = f.Key()
+		default:
+			out[i] = fmt.Sprintf("%s (sort: %s, display: %s, view: %s)", f.Path, f.Sort, f.Display, f.View)
+		}
+	}
+	return out
+}
+
+// Write renders the difference between from and to in the given format.
+func Write(w io.Writer, format Format, fromName, toName string, from, to config.Config) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, fromName, toName, Compare(from, to))
+	case FormatSideBySide:
+		return writeSideBySide(w, fromName, toName, Align(from, to))
+	default:
+		return writeUnified(w, fromName, toName, Align(from, to))
+	}
+}
+
+func writeJSON(w io.Writer, fromName, toName string, r Result) error {
+	if r.Changes == nil {
+		r.Changes = []Change{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		From    string   `json:"from"`
+		To      string   `json:"to"`
+		Changes []Change `json:"changes"`
+	}{fromName, toName, r.Changes})
+}
+
+func writeUnified(w io.Writer, fromName, toName string, hunks []Hunk) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+	for _, h := range hunks {
+		if len(h.Rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "@@ %s @@\n", h.Section)
+		for _, r := range h.Rows {
+			switch r.Op {
+			case OpEqual:
+				fmt.Fprintf(&b, " %s\n", r.Left)
+			case OpRemove:
+				fmt.Fprintf(&b, "-%s\n", r.Left)
+			case OpAdd:
+				fmt.Fprintf(&b, "+%s\n", r.Right)
+			case OpChanged:
+				fmt.Fprintf(&b, "-%s\n+%s\n", r.Left, r.Right)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeSideBySide(w io.Writer, fromName, toName string, hunks []Hunk) error {
+	width := len(fromName)
+	for _, h := range hunks {
+		for _, r := range h.Rows {
+			width = max(width, len(r.Left))
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-*s   %s\n", width, fromName, toName)
+	for _, h := range hunks {
+		if len(h.Rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "[%s]\n", h.Section)
+		for _, r := range h.Rows {
+			op := r.Op
+			if op == OpRemove {
+				op = '<'
+			} else if op == OpAdd {
+				op = '>'
+			}
+			fmt.Fprintln(&b, strings.TrimRight(fmt.Sprintf("%-*s %c %s", width, r.Left, op, r.Right), " "))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,97 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/5ouma/dorg/internal/config"
+)
+
+func Test_ParseFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		in      string
+		wantErr bool
+	}{
+		"unified":      {in: "unified", wantErr: false},
+		"json":         {in: "json", wantErr: false},
+		"side-by-side": {in: "side-by-side", wantErr: false},
+		"unknown":      {in: "html", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := ParseFormat(tc.in); (err != nil) != tc.wantErr {
+				t.Fatalf("ParseFormat(%s) error = %v, wantErr=%v", tc.in, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func Test_Write(t *testing.T) {
+	t.Parallel()
+
+	from := config.Config{Dock: config.Dock{
+		Apps:     []config.App{{Path: "/A.app"}, {Path: "/B.app"}},
+		Others:   []config.Folder{{Path: "~/Downloads", Sort: 1}},
+		Settings: &config.DockSettings{TileSize: 32},
+	}}
+	to := config.Config{Dock: config.Dock{
+		Apps:     []config.App{{Path: "/A.app"}, {Path: "/C.app"}},
+		Others:   []config.Folder{{Path: "~/Downloads", Sort: 2}},
+		Settings: &config.DockSettings{TileSize: 48},
+	}}
+
+	tests := map[string]struct {
+		format Format
+		want   []string
+	}{
+		"unified": {
+			format: FormatUnified,
+			want:   []string{"--- a.yml\n+++ dock\n", "@@ apps @@\n /A.app\n-/B.app\n+/C.app\n", "-tilesize: 32\n+tilesize: 48\n"},
+		},
+		"side-by-side": {
+			format: FormatSideBySide,
+			want:   []string{"[apps]\n", "/B.app", "<\n", "> /C.app", "tilesize: 32", "| tilesize: 48", "largesize: null"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := new(bytes.Buffer)
+			if err := Write(buf, tc.format, "a.yml", "dock", from, to); err != nil {
+				t.Fatalf("Write error: %v", err)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Fatalf("output missing %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		buf := new(bytes.Buffer)
+		if err := Write(buf, FormatJSON, "a.yml", "dock", from, to); err != nil {
+			t.Fatalf("Write error: %v", err)
+		}
+		var got struct {
+			From    string   `json:"from"`
+			To      string   `json:"to"`
+			Changes []Change `json:"changes"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("invalid json: %v\n%s", err, buf.String())
+		}
+		if got.From != "a.yml" || got.To != "dock" || len(got.Changes) != 4 {
+			t.Fatalf("unexpected json diff: %+v", got)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/5ouma/dorg/internal/utils"
+)
+
+var sectionTitles = []struct {
+	name  string
+	title string
+}{
+	{SectionApps, "Apps"},
+	{SectionOthers, "Folders"},
+	{SectionRecents, "Recents"},
+	{SectionSettings, "Settings"},
+	{SectionHotCorners, "Hot Corners"},
+}
+
+// Render writes a colourised, per-section report of r to w.
+func Render(w io.Writer, r Result) {
+	for _, s := range sectionTitles {
+		changes := r.Section(s.name)
+		if len(changes) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintln(w, utils.H2.Render(s.title))
+		for _, c := range changes {
+			_, _ = fmt.Fprintln(w, renderChange(c))
+		}
+	}
+}
+
+func renderChange(c Change) string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("%s %s", utils.AddedItem.Render(), c.Key)
+	case Removed:
+		return fmt.Sprintf("%s %s", utils.RemovedItem.Render(), c.Key)
+	case Moved:
+		return fmt.Sprintf("%s %s (#%d → #%d)", utils.MovedItem.Render(), c.Key, *c.FromIndex+1, *c.ToIndex+1)
+	case Modified:
+		if c.Field == "" {
+			return fmt.Sprintf("%s %s: %v → %v", utils.ModifiedItem.Render(), c.Key, c.From, c.To)
+		}
+		return fmt.Sprintf("%s %s %s: %v → %v", utils.ModifiedItem.Render(), c.Key, c.Field, c.From, c.To)
+	}
+	return c.Key
+}
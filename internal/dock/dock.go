@@ -4,14 +4,15 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"math/rand"
+	"math"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/5ouma/dorg/internal/config"
-	"github.com/5ouma/dorg/internal/utils"
-	"howett.net/plist"
+	"github.com/5ouma/dorg/internal/runner"
 )
 
 const (
@@ -23,6 +24,7 @@ const (
 type Plist struct {
 	PersistentApps        []PAItem `plist:"persistent-apps"`
 	PersistentOthers      []POItem `plist:"persistent-others"`
+	RecentApps            []PAItem `plist:"recent-apps,omitempty"`
 	TileSize              any      `plist:"tilesize,omitempty"`
 	LargeSize             any      `plist:"largesize,omitempty"`
 	Magnification         bool     `plist:"magnification"`
@@ -30,6 +32,39 @@ type Plist struct {
 	AutoHide              bool     `plist:"autohide"`
 	ShowRecents           bool     `plist:"show-recents"`
 	SizeImmutable         bool     `plist:"size-immutable"`
+
+	Orientation                    *string `plist:"orientation,omitempty"`
+	MinEffect                      *string `plist:"mineffect,omitempty"`
+	LaunchAnim                     *bool   `plist:"launchanim,omitempty"`
+	AutoHideDelay                  any     `plist:"autohide-delay,omitempty"`
+	AutoHideTimeModifier           any     `plist:"autohide-time-modifier,omitempty"`
+	ShowProcessIndicators          *bool   `plist:"show-process-indicators,omitempty"`
+	StaticOnly                     *bool   `plist:"static-only,omitempty"`
+	MRUSpaces                      *bool   `plist:"mru-spaces,omitempty"`
+	ShowHidden                     *bool   `plist:"showhidden,omitempty"`
+	ScrollToOpen                   *bool   `plist:"scroll-to-open,omitempty"`
+	ContentsImmutable              *bool   `plist:"contents-immutable,omitempty"`
+	PositionImmutable              *bool   `plist:"position-immutable,omitempty"`
+	MagnifyImmutable               *bool   `plist:"magnify-immutable,omitempty"`
+	MagSizeImmutable               *bool   `plist:"magsize-immutable,omitempty"`
+	AutoHideImmutable              *bool   `plist:"autohide-immutable,omitempty"`
+	MinEffectImmutable             *bool   `plist:"mineffect-immutable,omitempty"`
+	LaunchAnimImmutable            *bool   `plist:"launchanim-immutable,omitempty"`
+	ShowProcessIndicatorsImmutable *bool   `plist:"show-process-indicators-immutable,omitempty"`
+	MinimizeToApplicationImmutable *bool   `plist:"min-in-place-immutable,omitempty"`
+
+	TopLeftCorner       *int `plist:"wvous-tl-corner,omitempty"`
+	TopLeftModifier     *int `plist:"wvous-tl-modifier,omitempty"`
+	TopRightCorner      *int `plist:"wvous-tr-corner,omitempty"`
+	TopRightModifier    *int `plist:"wvous-tr-modifier,omitempty"`
+	BottomLeftCorner    *int `plist:"wvous-bl-corner,omitempty"`
+	BottomLeftModifier  *int `plist:"wvous-bl-modifier,omitempty"`
+	BottomRightCorner   *int `plist:"wvous-br-corner,omitempty"`
+	BottomRightModifier *int `plist:"wvous-br-modifier,omitempty"`
+
+	raw      raw
+	location *Location
+	guids    guids
 }
 
 type FileData struct {
@@ -43,37 +78,79 @@ type TileData struct {
 }
 
 func (d TileData) GetPath() string {
-	out := strings.TrimPrefix(d.FileData.URLString, "file://")
-	out = strings.TrimSuffix(out, "/")
-	return strings.ReplaceAll(out, "%20", " ")
+	return d.FileData.Path()
 }
 
 type PAItem struct {
 	GUID     int      `plist:"GUID,omitempty"`
 	TileType string   `plist:"tile-type"`
 	TileData TileData `plist:"tile-data"`
+
+	raw raw
 }
 
 type POItem struct {
 	GUID     int        `plist:"GUID"`
 	TileType string     `plist:"tile-type"`
 	TileData POTileData `plist:"tile-data"`
+
+	raw raw
 }
 
+const (
+	directoryTile   = "directory-tile"
+	fileTile        = "file-tile"
+	urlTile         = "url-tile"
+	smallSpacerTile = "small-spacer-tile"
+	spacerTileType  = "spacer-tile"
+	flexSpacerTile  = "flex-spacer-tile"
+)
+
 type POTileData struct {
-	Arrangement int      `plist:"arrangement"`
-	DisplayAs   int      `plist:"displayas"`
-	ShowAs      int      `plist:"showas"`
-	FileData    FileData `plist:"file-data"`
-	FileLabel   string   `plist:"file-label"`
-	FileType    int      `plist:"file-type"`
-	Directory   int      `plist:"directory,omitempty"`
+	Arrangement int       `plist:"arrangement"`
+	DisplayAs   int       `plist:"displayas"`
+	ShowAs      int       `plist:"showas"`
+	FileData    FileData  `plist:"file-data"`
+	FileLabel   string    `plist:"file-label"`
+	FileType    int       `plist:"file-type"`
+	Directory   int       `plist:"directory,omitempty"`
+	Label       string    `plist:"label,omitempty"`
+	URL         *FileData `plist:"url,omitempty"`
+}
+
+// urlTileData and fileTileData are the tile data of the url and file tiles
+// of the others section, which have none of the keys of a folder.
+type urlTileData struct {
+	Label string    `plist:"label"`
+	URL   *FileData `plist:"url"`
+}
+
+type fileTileData struct {
+	FileData  FileData `plist:"file-data"`
+	FileLabel string   `plist:"file-label"`
+	FileType  int      `plist:"file-type"`
+}
+
+// MarshalPlist writes only the tile data keys the Dock uses for the tile type.
+func (i POItem) MarshalPlist() (any, error) {
+	var data any = i.TileData
+	switch i.TileType {
+	case urlTile:
+		data = urlTileData{Label: i.TileData.Label, URL: i.TileData.URL}
+	case fileTile:
+		data = fileTileData{FileData: i.TileData.FileData, FileLabel: i.TileData.FileLabel, FileType: i.TileData.FileType}
+	case smallSpacerTile, spacerTileType, flexSpacerTile:
+		data = map[string]any{}
+	}
+	return struct {
+		GUID     int    `plist:"GUID"`
+		TileType string `plist:"tile-type"`
+		TileData any    `plist:"tile-data"`
+	}{i.GUID, i.TileType, data}, nil
 }
 
 func (d POTileData) GetPath() string {
-	out := strings.TrimPrefix(d.FileData.URLString, "file://")
-	out = strings.TrimSuffix(out, "/")
-	return strings.ReplaceAll(out, "%20", " ")
+	return d.FileData.Path()
 }
 
 func fileNameWithoutExtTrimSuffix(fileName string) string {
@@ -81,78 +158,145 @@ func fileNameWithoutExtTrimSuffix(fileName string) string {
 	return strings.TrimSuffix(fileName, filepath.Ext(fileName))
 }
 
-func LoadDockPlist() (*Plist, error) {
-	home, err := os.UserHomeDir()
+func LoadPlistFile(path string) (*Plist, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user home directory: %v", err)
+		return nil, fmt.Errorf("failed to read dock plist: %v", err)
 	}
 
-	dPlist := new(Plist)
-	data, err := os.ReadFile(filepath.Join(home, dockPlistPath))
-	if err != nil {
-		return nil, fmt.Errorf("failed to read dock plist: %v", err)
+	return ParsePlist(data)
+}
+
+func (p *Plist) AddApp(app config.App) {
+	p.PersistentApps = append(p.PersistentApps, p.newAppItem(persistentAppsKey, app))
+}
+
+// AddRecent appends an app to the recent applications section of the Dock.
+func (p *Plist) AddRecent(appPath string) {
+	p.RecentApps = append(p.RecentApps, p.newAppItem(recentAppsKey, config.App{Path: appPath}))
+}
+
+// ClearRecents empties the recent applications section of the Dock.
+func (p *Plist) ClearRecents() {
+	p.RecentApps = []PAItem{}
+}
+
+func (p *Plist) newAppItem(section string, app config.App) PAItem {
+	item := appItem(app)
+	item.GUID = p.guid(section, item.key())
+	return item
+}
+
+// appItem builds the tile of app, without a GUID.
+func appItem(app config.App) PAItem {
+	if tileType := spacerTile(app.Spacer, app.FlexSpacer); tileType != "" {
+		return PAItem{TileType: tileType}
 	}
-	if _, err := plist.Unmarshal(data, dPlist); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal dock plist: %v", err)
+	return PAItem{
+		TileType: fileTile,
+		TileData: TileData{FileData: newFileData(app.Path, true), FileType: 41},
 	}
-
-	return dPlist, nil
 }
 
-func (p *Plist) AddApp(appPath string) {
-	var paItem PAItem
-	switch appPath {
-	case "":
-		paItem = PAItem{TileType: "small-spacer-tile"}
-	case " ":
-		paItem = PAItem{TileType: "spacer-tile"}
-	default:
-		paItem = PAItem{
-			GUID:     rand.Intn(9999999999),
-			TileType: "file-tile",
-			TileData: TileData{FileData: FileData{URLString: appPath, URLStringType: 0}, FileType: 41},
-		}
+func spacerTile(spacer config.Spacer, flex bool) string {
+	switch {
+	case flex:
+		return flexSpacerTile
+	case spacer == config.SpacerSmall:
+		return smallSpacerTile
+	case spacer == config.SpacerLarge:
+		return spacerTileType
 	}
+	return ""
+}
 
-	p.PersistentApps = append(p.PersistentApps, paItem)
+// tileSpacer is the reverse of spacerTile.
+func tileSpacer(tileType string) (config.Spacer, bool, bool) {
+	switch tileType {
+	case flexSpacerTile:
+		return "", true, true
+	case smallSpacerTile:
+		return config.SpacerSmall, false, true
+	case spacerTileType:
+		return config.SpacerLarge, false, true
+	}
+	return "", false, false
 }
 
 func (p *Plist) AddOther(other config.Folder) error {
-	path := other.Path
-	home, err := os.UserHomeDir()
+	poItem, err := p.otherItem(other)
 	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %v", err)
+		return err
 	}
+	poItem.GUID = p.guid(persistentOthersKey, poItem.key())
+	p.PersistentOthers = append(p.PersistentOthers, poItem)
 
-	if path == "~" {
-		path = home
-	} else if after, ok := strings.CutPrefix(path, "~/"); ok {
-		path = filepath.Join(home, after)
-	} else {
-		return fmt.Errorf("invalid path '%s': must be absolute or start with '~/'", other.Path)
-	}
-	path, err = filepath.Abs(path)
-	if err != nil {
-		return fmt.Errorf("failed to get absolute path for '%s': %v", path, err)
+	return nil
+}
+
+// otherItem builds the tile of other, without a GUID.
+func (p *Plist) otherItem(other config.Folder) (POItem, error) {
+	if tileType := spacerTile(other.Spacer, other.FlexSpacer); tileType != "" {
+		return POItem{TileType: tileType}, nil
 	}
 
-	poItem := POItem{
-		GUID:     rand.Intn(9999999999),
-		TileType: "directory-tile",
-		TileData: POTileData{
-			Directory:   1,
-			Arrangement: other.Sort,
-			DisplayAs:   other.Display,
-			ShowAs:      other.View,
-			FileData:    FileData{URLString: path, URLStringType: 0},
-			FileLabel:   fileNameWithoutExtTrimSuffix(other.Path),
-			FileType:    2,
-		},
+	var poItem POItem
+	switch {
+	case other.URL != "":
+		label := other.Label
+		if label == "" {
+			label = other.URL
+		}
+		poItem = POItem{
+			TileType: urlTile,
+			TileData: POTileData{Label: label, URL: &FileData{URLString: other.URL, URLStringType: urlStringTypeURL}},
+		}
+	case other.File != "":
+		path, err := p.expandPath(other.File)
+		if err != nil {
+			return POItem{}, err
+		}
+		poItem = POItem{
+			TileType: fileTile,
+			TileData: POTileData{
+				FileData:  newFileData(path, false),
+				FileLabel: fileNameWithoutExtTrimSuffix(other.File),
+			},
+		}
+	default:
+		path, err := p.expandPath(other.Path)
+		if err != nil {
+			return POItem{}, err
+		}
+		poItem = POItem{
+			TileType: directoryTile,
+			TileData: POTileData{
+				Directory:   1,
+				Arrangement: int(other.Sort),
+				DisplayAs:   int(other.Display),
+				ShowAs:      int(other.View),
+				FileData:    newFileData(path, true),
+				FileLabel:   fileNameWithoutExtTrimSuffix(other.Path),
+				FileType:    2,
+			},
+		}
 	}
 
-	p.PersistentOthers = append(p.PersistentOthers, poItem)
+	return poItem, nil
+}
 
-	return nil
+// expandPath turns a config path into the absolute path stored in the plist.
+func (p *Plist) expandPath(path string) (string, error) {
+	l, err := p.getLocation()
+	if err != nil {
+		return "", err
+	}
+
+	expanded := config.ExpandPath(path, l.Home)
+	if !filepath.IsAbs(expanded) {
+		return "", fmt.Errorf("invalid path '%s': must be absolute or start with '~/'", path)
+	}
+	return filepath.Clean(expanded), nil
 }
 
 func (p *Plist) ApplySettings(setting config.DockSettings) error {
@@ -162,34 +306,117 @@ func (p *Plist) ApplySettings(setting config.DockSettings) error {
 	p.ShowRecents = setting.ShowRecents
 	p.SizeImmutable = setting.SizeImmutable
 
-	switch v := setting.TileSize.(type) {
-	case float64:
-		if v < 16 && v > 128 {
-			return fmt.Errorf("tile size must be between 16 and 128: %d", setting.TileSize)
-		}
-	case int:
-		if v < 16 && v > 128 {
-			return fmt.Errorf("tile size must be between 16 and 128: %d", setting.TileSize)
-		}
+	if err := checkNumber("tile size", setting.TileSize, 16, 128); err != nil {
+		return err
+	}
+	if err := checkNumber("large size", setting.LargeSize, 16, 128); err != nil {
+		return err
+	}
+	if err := checkNumber("autohide delay", setting.AutoHideDelay, 0, math.Inf(1)); err != nil {
+		return err
+	}
+	if err := checkNumber("autohide time modifier", setting.AutoHideTimeModifier, 0, math.Inf(1)); err != nil {
+		return err
+	}
+	if err := checkChoice("orientation", setting.Orientation, config.Orientations); err != nil {
+		return err
 	}
+	if err := checkChoice("minimize effect", setting.MinEffect, config.MinEffects); err != nil {
+		return err
+	}
+	setNumber(&p.TileSize, setting.TileSize)
+	setNumber(&p.LargeSize, setting.LargeSize)
+	setNumber(&p.AutoHideDelay, setting.AutoHideDelay)
+	setNumber(&p.AutoHideTimeModifier, setting.AutoHideTimeModifier)
+	setIfSet(&p.Orientation, setting.Orientation)
+	setIfSet(&p.MinEffect, setting.MinEffect)
+	setIfSet(&p.LaunchAnim, setting.LaunchAnim)
+	setIfSet(&p.ShowProcessIndicators, setting.ShowProcessIndicators)
+	setIfSet(&p.StaticOnly, setting.StaticOnly)
+	setIfSet(&p.MRUSpaces, setting.MRUSpaces)
+	setIfSet(&p.ShowHidden, setting.ShowHidden)
+	setIfSet(&p.ScrollToOpen, setting.ScrollToOpen)
+	setIfSet(&p.ContentsImmutable, setting.ContentsImmutable)
+	setIfSet(&p.PositionImmutable, setting.PositionImmutable)
+	setIfSet(&p.MagnifyImmutable, setting.MagnifyImmutable)
+	setIfSet(&p.MagSizeImmutable, setting.MagSizeImmutable)
+	setIfSet(&p.AutoHideImmutable, setting.AutoHideImmutable)
+	setIfSet(&p.MinEffectImmutable, setting.MinEffectImmutable)
+	setIfSet(&p.LaunchAnimImmutable, setting.LaunchAnimImmutable)
+	setIfSet(&p.ShowProcessIndicatorsImmutable, setting.ShowProcessIndicatorsImmutable)
+	setIfSet(&p.MinimizeToApplicationImmutable, setting.MinimizeToApplicationImmutable)
 
-	switch v := setting.LargeSize.(type) {
-	case float64:
-		if v < 16 && v > 128 {
-			return fmt.Errorf("large size must be between 16 and 128: %d", setting.LargeSize)
+	return nil
+}
+
+// setIfSet leaves dst untouched when the config does not set the value.
+func setIfSet[T any](dst *T, v T) {
+	if !reflect.ValueOf(&v).Elem().IsZero() {
+		*dst = v
+	}
+}
+
+// setNumber is setIfSet for numbers, keeping the value of dst when it is equal
+// to v, so that a plist real is not rewritten as an integer.
+func setNumber(dst *any, v any) {
+	if cur, ok := number(*dst); ok {
+		if n, ok := number(v); ok && n == cur {
+			return
 		}
+	}
+	setIfSet(dst, v)
+}
+
+func number(n any) (float64, bool) {
+	switch n := n.(type) {
 	case int:
-		if v < 16 && v > 128 {
-			return fmt.Errorf("large size must be between 16 and 128: %d", setting.LargeSize)
-		}
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
 	}
+	return 0, false
+}
 
+func checkNumber(name string, n any, minimum, maximum float64) error {
+	if n == nil {
+		return nil
+	}
+	v, ok := number(n)
+	if !ok {
+		return fmt.Errorf("%s must be a number: %v", name, n)
+	}
+	if v < minimum || v > maximum {
+		if math.IsInf(maximum, 1) {
+			return fmt.Errorf("%s must be at least %g: %v", name, minimum, n)
+		}
+		return fmt.Errorf("%s must be between %g and %g: %v", name, minimum, maximum, n)
+	}
 	return nil
 }
 
-func (p *Plist) Save() error {
-	if err := p.unload(); err != nil {
-		return fmt.Errorf("dock save: %w", err)
+func checkChoice(name string, v *string, choices []string) error {
+	if v == nil || slices.Contains(choices, *v) {
+		return nil
+	}
+	return fmt.Errorf("%s must be one of %s: %s", name, strings.Join(choices, ", "), *v)
+}
+
+func (p *Plist) Save(r runner.Runner) error {
+	l, err := p.getLocation()
+	if err != nil {
+		return err
+	}
+
+	// The launch agent only runs the current user's Dock.
+	current := l.Current()
+	if current {
+		if err := p.unload(r); err != nil {
+			return fmt.Errorf("dock save: %w", err)
+		}
 	}
 
 	file, err := os.CreateTemp("", "dock.plist")
@@ -202,42 +429,52 @@ func (p *Plist) Save() error {
 		}
 	}()
 
+	data, err := p.Marshal()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
 	slog.Debug("writing temp dock plist", "plist", file.Name())
-	if err := plist.NewBinaryEncoder(file).Encode(p); err != nil {
-		return fmt.Errorf("failed to decode plist: %w", err)
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write temp dock plist: %w", err)
 	}
 	if err := file.Close(); err != nil {
 		return fmt.Errorf("failed to close temp file: %v", err)
 	}
 
-	if err := p.importPlist(file.Name()); err != nil {
+	if err := p.importPlist(r, l.domain(), file.Name()); err != nil {
 		return fmt.Errorf("failed to import plist: %w", err)
 	}
-	return p.restart()
+	if !current {
+		return nil
+	}
+	return p.restart(r)
 }
 
-func (p *Plist) importPlist(path string) error {
-	slog.Debug("importing dock plist")
-	if _, err := utils.RunCommand(context.Background(), "/usr/bin/defaults", "import", "com.apple.dock", path); err != nil {
+func (p *Plist) importPlist(r runner.Runner, domain, path string) error {
+	slog.Debug("importing dock plist", "domain", domain)
+	if _, err := r.Run(context.Background(), "/usr/bin/defaults", "import", domain, path); err != nil {
 		return fmt.Errorf("failed to defaults import dock plist '%s': %v", path, err)
 	}
 	return nil
 }
 
-func (p *Plist) unload() error {
+func (p *Plist) unload(r runner.Runner) error {
 	slog.Debug("unloading Dock launch agent")
-	if _, err := utils.RunCommand(context.Background(), "/bin/launchctl", "unload", dockLaunchAgentPath); err != nil {
+	if _, err := r.Run(context.Background(), "/bin/launchctl", "unload", dockLaunchAgentPath); err != nil {
 		return fmt.Errorf("failed to unload Dock launch agent: %v", err)
 	}
 	return nil
 }
 
-func (p *Plist) restart() error {
+func (p *Plist) restart(r runner.Runner) error {
 	slog.Debug("restart Dock launch agent")
-	if _, err := utils.RunCommand(context.Background(), "/bin/launchctl", "load", dockLaunchAgentPath); err != nil {
+	if _, err := r.Run(context.Background(), "/bin/launchctl", "load", dockLaunchAgentPath); err != nil {
 		return fmt.Errorf("failed to load Dock launch agent: %v", err)
 	}
-	if _, err := utils.RunCommand(context.Background(), "/bin/launchctl", "start", dockLaunchAgentID); err != nil {
+	if _, err := r.Run(context.Background(), "/bin/launchctl", "start", dockLaunchAgentID); err != nil {
 		return fmt.Errorf("failed to start Dock launch agent: %v", err)
 	}
 	return nil
@@ -246,29 +483,47 @@ func (p *Plist) restart() error {
 func (p *Plist) GenerateConfigFromPlist() (config.Config, error) {
 	conf := new(config.Config)
 
-	home, err := os.UserHomeDir()
+	l, err := p.getLocation()
 	if err != nil {
 		return *conf, fmt.Errorf("failed to get user home dir: %w", err)
 	}
+	home := l.Home
 
-	fmt.Println(utils.H2.Render("Apps"))
 	for _, item := range p.PersistentApps {
-		fmt.Println(utils.CheckedItem.Render(), item.TileData.GetPath())
-		conf.Dock.Apps = append(conf.Dock.Apps, item.TileData.GetPath())
+		if spacer, flex, ok := tileSpacer(item.TileType); ok {
+			conf.Dock.Apps = append(conf.Dock.Apps, config.App{Spacer: spacer, FlexSpacer: flex})
+			continue
+		}
+		conf.Dock.Apps = append(conf.Dock.Apps, config.App{Path: item.TileData.GetPath()})
+	}
+
+	for _, item := range p.RecentApps {
+		conf.Dock.Recents = append(conf.Dock.Recents, item.TileData.GetPath())
 	}
 
-	fmt.Println(utils.H2.Render("Folders"))
 	for _, item := range p.PersistentOthers {
-		path := item.TileData.GetPath()
-		if relPath, err := filepath.Rel(home, path); err == nil {
-			path = filepath.Join("~", relPath)
+		if spacer, flex, ok := tileSpacer(item.TileType); ok {
+			conf.Dock.Others = append(conf.Dock.Others, config.Folder{Spacer: spacer, FlexSpacer: flex})
+			continue
 		}
-		fmt.Println(utils.CheckedItem.Render(), path)
+		switch item.TileType {
+		case urlTile:
+			other := config.Folder{Label: item.TileData.Label}
+			if item.TileData.URL != nil {
+				other.URL = item.TileData.URL.URLString
+			}
+			conf.Dock.Others = append(conf.Dock.Others, other)
+			continue
+		case fileTile:
+			conf.Dock.Others = append(conf.Dock.Others, config.Folder{File: config.ContractPath(item.TileData.GetPath(), home)})
+			continue
+		}
+
 		conf.Dock.Others = append(conf.Dock.Others, config.Folder{
-			Path:    path,
-			Sort:    item.TileData.Arrangement,
-			Display: item.TileData.DisplayAs,
-			View:    item.TileData.ShowAs,
+			Path:    config.ContractPath(item.TileData.GetPath(), home),
+			Sort:    config.Sort(item.TileData.Arrangement),
+			Display: config.Display(item.TileData.DisplayAs),
+			View:    config.View(item.TileData.ShowAs),
 		})
 	}
 
@@ -279,7 +534,29 @@ func (p *Plist) GenerateConfigFromPlist() (config.Config, error) {
 		MinimizeToApplication: p.MinimizeToApplication,
 		AutoHide:              p.AutoHide,
 		ShowRecents:           p.ShowRecents,
+		SizeImmutable:         p.SizeImmutable,
+
+		Orientation:                    p.Orientation,
+		MinEffect:                      p.MinEffect,
+		LaunchAnim:                     p.LaunchAnim,
+		AutoHideDelay:                  p.AutoHideDelay,
+		AutoHideTimeModifier:           p.AutoHideTimeModifier,
+		ShowProcessIndicators:          p.ShowProcessIndicators,
+		StaticOnly:                     p.StaticOnly,
+		MRUSpaces:                      p.MRUSpaces,
+		ShowHidden:                     p.ShowHidden,
+		ScrollToOpen:                   p.ScrollToOpen,
+		ContentsImmutable:              p.ContentsImmutable,
+		PositionImmutable:              p.PositionImmutable,
+		MagnifyImmutable:               p.MagnifyImmutable,
+		MagSizeImmutable:               p.MagSizeImmutable,
+		AutoHideImmutable:              p.AutoHideImmutable,
+		MinEffectImmutable:             p.MinEffectImmutable,
+		LaunchAnimImmutable:            p.LaunchAnimImmutable,
+		ShowProcessIndicatorsImmutable: p.ShowProcessIndicatorsImmutable,
+		MinimizeToApplicationImmutable: p.MinimizeToApplicationImmutable,
 	}
+	conf.HotCorners = p.hotCorners()
 
 	return *conf, nil
 }
@@ -3,11 +3,15 @@ package dock
 import (
 	"bytes"
 	"encoding/json"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/5ouma/dorg/internal/config"
+	"howett.net/plist"
 )
 
 func Test_FileNameWithoutExtTrimSuffix(t *testing.T) {
@@ -50,12 +54,13 @@ func Test_AddApp(t *testing.T) {
 	t.Parallel()
 
 	tests := map[string]struct {
-		in       string
+		in       config.App
 		wantType string
 	}{
-		"empty spacer": {in: "", wantType: "small-spacer-tile"},
-		"spacer":       {in: " ", wantType: "spacer-tile"},
-		"normal":       {in: "/Applications/Calculator.app", wantType: "file-tile"},
+		"small spacer": {in: config.App{Spacer: config.SpacerSmall}, wantType: "small-spacer-tile"},
+		"large spacer": {in: config.App{Spacer: config.SpacerLarge}, wantType: "spacer-tile"},
+		"flex spacer":  {in: config.App{FlexSpacer: true}, wantType: "flex-spacer-tile"},
+		"normal":       {in: config.App{Path: "/Applications/Calculator.app"}, wantType: "file-tile"},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -84,6 +89,8 @@ func Test_AddOther(t *testing.T) {
 	}{
 		"tilde":     {in: config.Folder{Path: "~"}, wantErr: false},
 		"tilde sub": {in: config.Folder{Path: "~/Documents"}, wantErr: false},
+		"absolute":  {in: config.Folder{Path: "/Users/Shared/"}, wantErr: false},
+		"env":       {in: config.Folder{Path: "${HOME}/Documents"}, wantErr: false},
 		"invalid":   {in: config.Folder{Path: "relative/path"}, wantErr: true},
 	}
 	for name, tc := range tests {
@@ -96,11 +103,17 @@ func Test_AddOther(t *testing.T) {
 				t.Fatalf("%v err=%v, wantErr=%v", tc.in, err, tc.wantErr)
 			}
 			if err == nil {
-				got := p.PersistentOthers[0].TileData.FileData.URLString
+				if data := p.PersistentOthers[0].TileData.FileData; data.URLStringType != 15 || !strings.HasPrefix(data.URLString, "file://") {
+					t.Fatalf("expected a file URL, got %+v", data)
+				}
+				got := p.PersistentOthers[0].TileData.GetPath()
 				if tc.in.Path == "~" && got != home {
 					t.Fatalf("expected %s got %s", home, got)
 				}
-				if tc.in.Path == "~/Documents" {
+				if tc.in.Path == "/Users/Shared/" && got != "/Users/Shared" {
+					t.Fatalf("expected /Users/Shared got %s", got)
+				}
+				if tc.in.Path == "~/Documents" || tc.in.Path == "${HOME}/Documents" {
 					if got != filepath.Join(home, "Documents") {
 						t.Fatalf("expected %s got %s", filepath.Join(home, "Documents"), got)
 					}
@@ -110,6 +123,64 @@ func Test_AddOther(t *testing.T) {
 	}
 }
 
+func Test_AddOther_TileKinds(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		in       config.Folder
+		tileType string
+		keys     []string
+		want     config.Folder
+	}{
+		"folder":            {in: config.Folder{Path: "~/Downloads", Sort: config.SortKind}, tileType: "directory-tile", keys: []string{"arrangement", "directory", "displayas", "file-data", "file-label", "file-type", "showas"}, want: config.Folder{Path: "~/Downloads", Sort: config.SortKind}},
+		"file":              {in: config.Folder{File: "~/Documents/notes.txt"}, tileType: "file-tile", keys: []string{"file-data", "file-label", "file-type"}, want: config.Folder{File: "~/Documents/notes.txt"}},
+		"url":               {in: config.Folder{URL: "smb://server/share", Label: "Share"}, tileType: "url-tile", keys: []string{"label", "url"}, want: config.Folder{URL: "smb://server/share", Label: "Share"}},
+		"spacer":            {in: config.Folder{Spacer: config.SpacerLarge}, tileType: "spacer-tile", keys: []string{}, want: config.Folder{Spacer: config.SpacerLarge}},
+		"url label default": {in: config.Folder{URL: "https://intranet.example.com"}, tileType: "url-tile", keys: []string{"label", "url"}, want: config.Folder{URL: "https://intranet.example.com", Label: "https://intranet.example.com"}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Plist{}
+			if err := p.AddOther(tc.in); err != nil {
+				t.Fatalf("AddOther error: %v", err)
+			}
+			if got := p.PersistentOthers[0].TileType; got != tc.tileType {
+				t.Fatalf("tile type = %s, want %s", got, tc.tileType)
+			}
+
+			data, err := p.Marshal()
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			var encoded struct {
+				Others []struct {
+					TileData map[string]any `plist:"tile-data"`
+				} `plist:"persistent-others"`
+			}
+			if _, err := plist.Unmarshal(data, &encoded); err != nil {
+				t.Fatalf("failed to decode plist: %v", err)
+			}
+			if got := slices.Sorted(maps.Keys(encoded.Others[0].TileData)); !slices.Equal(got, tc.keys) {
+				t.Fatalf("tile-data keys = %v, want %v", got, tc.keys)
+			}
+
+			parsed, err := ParsePlist(data)
+			if err != nil {
+				t.Fatalf("ParsePlist error: %v", err)
+			}
+			conf, err := parsed.GenerateConfigFromPlist()
+			if err != nil {
+				t.Fatalf("GenerateConfigFromPlist error: %v", err)
+			}
+			if got := conf.Dock.Others[0]; got != tc.want {
+				t.Fatalf("others = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
 func Test_ApplySettings(t *testing.T) {
 	t.Parallel()
 
@@ -119,19 +190,50 @@ func Test_ApplySettings(t *testing.T) {
 	}{
 		"valid sizes int":   {in: config.DockSettings{TileSize: 32, LargeSize: 64, Magnification: true}, wantErr: false},
 		"valid sizes float": {in: config.DockSettings{TileSize: 32.0, LargeSize: 64.0}, wantErr: false},
+		"no sizes":          {in: config.DockSettings{AutoHide: true}, wantErr: false},
+		"tile too small":    {in: config.DockSettings{TileSize: 8}, wantErr: true},
+		"large too big":     {in: config.DockSettings{LargeSize: 256.0}, wantErr: true},
+		"size not number":   {in: config.DockSettings{TileSize: "big"}, wantErr: true},
+		"optional settings": {in: config.DockSettings{Orientation: ptr("left"), MinEffect: ptr("scale"), AutoHideDelay: 0.5, LaunchAnim: ptr(false), ContentsImmutable: ptr(true)}, wantErr: false},
+		"bad orientation":   {in: config.DockSettings{Orientation: ptr("top")}, wantErr: true},
+		"bad mineffect":     {in: config.DockSettings{MinEffect: ptr("fade")}, wantErr: true},
+		"negative delay":    {in: config.DockSettings{AutoHideDelay: -1}, wantErr: true},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
 			p := &Plist{}
-			if err := p.ApplySettings(tc.in); (err != nil) != tc.wantErr {
+			err := p.ApplySettings(tc.in)
+			if (err != nil) != tc.wantErr {
 				t.Fatalf("ApplySettings error = %v, wantErr=%v", err, tc.wantErr)
 			}
+			if err == nil && (p.TileSize != tc.in.TileSize || p.LargeSize != tc.in.LargeSize) {
+				t.Fatalf("sizes = %v/%v, want %v/%v", p.TileSize, p.LargeSize, tc.in.TileSize, tc.in.LargeSize)
+			}
+			if err == nil && (p.Orientation != tc.in.Orientation || p.LaunchAnim != tc.in.LaunchAnim || p.AutoHideDelay != tc.in.AutoHideDelay) {
+				t.Fatalf("optional settings = %v/%v/%v, want %v/%v/%v", p.Orientation, p.LaunchAnim, p.AutoHideDelay, tc.in.Orientation, tc.in.LaunchAnim, tc.in.AutoHideDelay)
+			}
 		})
 	}
 }
 
+func Test_ApplySettings_KeepsUnset(t *testing.T) {
+	t.Parallel()
+
+	p := &Plist{Orientation: ptr("right"), MRUSpaces: ptr(false), AutoHideTimeModifier: 0.25}
+	if err := p.ApplySettings(config.DockSettings{MinEffect: ptr("suck")}); err != nil {
+		t.Fatalf("ApplySettings error: %v", err)
+	}
+	if *p.Orientation != "right" || *p.MRUSpaces != false || p.AutoHideTimeModifier != 0.25 || *p.MinEffect != "suck" {
+		t.Fatalf("settings = %v/%v/%v/%v", *p.Orientation, *p.MRUSpaces, p.AutoHideTimeModifier, *p.MinEffect)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
 func Test_GenerateConfigFromPlist(t *testing.T) {
 	t.Parallel()
 
@@ -142,19 +244,29 @@ func Test_GenerateConfigFromPlist(t *testing.T) {
 	}{
 		"all": {
 			plist: Plist{
-				PersistentApps:        []PAItem{{TileData: TileData{FileData: FileData{URLString: "file:///Applications/Calculator.app/"}}}},
-				PersistentOthers:      []POItem{{TileData: POTileData{Arrangement: 1, DisplayAs: 2, ShowAs: 3, FileData: FileData{URLString: filepath.Join(home, "Documents") + "/"}}}},
+				PersistentApps: []PAItem{{TileData: TileData{FileData: FileData{URLString: "file:///Applications/Calculator.app/"}}}, {TileType: "flex-spacer-tile"}},
+				PersistentOthers: []POItem{
+					{TileData: POTileData{Arrangement: 1, DisplayAs: 2, ShowAs: 3, FileData: FileData{URLString: filepath.Join(home, "Documents") + "/"}}},
+					{TileData: POTileData{Arrangement: 1, FileData: FileData{URLString: "file:///Applications/"}}},
+				},
 				TileSize:              32,
 				LargeSize:             64,
 				Magnification:         true,
 				MinimizeToApplication: true,
 				AutoHide:              true,
 				ShowRecents:           true,
+				SizeImmutable:         true,
+				Orientation:           ptr("left"),
+				AutoHideDelay:         0.2,
+				StaticOnly:            ptr(false),
 			},
 			want: config.Config{Dock: config.Dock{
-				Apps:     []string{"/Applications/Calculator.app"},
-				Others:   []config.Folder{{Path: "~/Documents", Sort: 1, Display: 2, View: 3}},
-				Settings: &config.DockSettings{TileSize: 32, LargeSize: 64, Magnification: true, MinimizeToApplication: true, AutoHide: true, ShowRecents: true},
+				Apps:   []config.App{{Path: "/Applications/Calculator.app"}, {FlexSpacer: true}},
+				Others: []config.Folder{{Path: "~/Documents", Sort: 1, Display: 2, View: 3}, {Path: "/Applications", Sort: 1}},
+				Settings: &config.DockSettings{
+					TileSize: 32, LargeSize: 64, Magnification: true, MinimizeToApplication: true, AutoHide: true, ShowRecents: true, SizeImmutable: true,
+					Orientation: ptr("left"), AutoHideDelay: 0.2, StaticOnly: ptr(false),
+				},
 			}},
 		},
 	}
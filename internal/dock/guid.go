@@ -0,0 +1,81 @@
+package dock
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+const maxGUID = 9999999999
+
+// guids hands out tile GUIDs. A tile that was already in the plist keeps its
+// GUID; new tiles get one derived from the seed, section and path, so that
+// repeated loads of the same config produce the same plist.
+type guids struct {
+	seed     int64
+	existing map[string][]int
+	issued   map[string]int
+}
+
+// SetSeed changes the GUIDs given to new tiles.
+func (p *Plist) SetSeed(seed int64) {
+	p.guids.seed = seed
+}
+
+// indexGUIDs remembers the GUIDs of the tiles currently in the plist.
+func (p *Plist) indexGUIDs() {
+	p.guids.existing = map[string][]int{}
+	remember := func(section, key string, guid int) {
+		if guid != 0 && key != "" {
+			k := guidKey(section, key)
+			p.guids.existing[k] = append(p.guids.existing[k], guid)
+		}
+	}
+	for _, item := range p.PersistentApps {
+		remember(persistentAppsKey, item.key(), item.GUID)
+	}
+	for _, item := range p.RecentApps {
+		remember(recentAppsKey, item.key(), item.GUID)
+	}
+	for _, item := range p.PersistentOthers {
+		remember(persistentOthersKey, item.key(), item.GUID)
+	}
+}
+
+func (p *Plist) guid(section, key string) int {
+	k := guidKey(section, key)
+	if ids := p.guids.existing[k]; len(ids) > 0 {
+		p.guids.existing[k] = ids[1:]
+		return ids[0]
+	}
+
+	if p.guids.issued == nil {
+		p.guids.issued = map[string]int{}
+	}
+	n := p.guids.issued[k]
+	p.guids.issued[k]++
+
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%d\x00%s\x00%d", p.guids.seed, k, n)
+	return int(h.Sum64()%maxGUID) + 1
+}
+
+func guidKey(section, key string) string {
+	return section + "\x00" + key
+}
+
+func (i PAItem) key() string {
+	if _, _, ok := tileSpacer(i.TileType); ok {
+		return i.TileType
+	}
+	return i.TileData.GetPath()
+}
+
+func (i POItem) key() string {
+	if _, _, ok := tileSpacer(i.TileType); ok {
+		return i.TileType
+	}
+	if i.TileType == urlTile && i.TileData.URL != nil {
+		return i.TileData.URL.URLString
+	}
+	return i.TileData.GetPath()
+}
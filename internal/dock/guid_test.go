@@ -0,0 +1,77 @@
+package dock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/5ouma/dorg/internal/config"
+)
+
+func Test_guid_ReusesExisting(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "dock.plist"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	p, err := ParsePlist(data)
+	if err != nil {
+		t.Fatalf("ParsePlist error: %v", err)
+	}
+	if len(p.PersistentApps) == 0 {
+		t.Fatal("fixture has no apps")
+	}
+
+	old := p.PersistentApps
+	p.PersistentApps = nil
+	for _, item := range old {
+		spacer, flex, ok := tileSpacer(item.TileType)
+		if ok {
+			p.AddApp(config.App{Spacer: spacer, FlexSpacer: flex})
+			continue
+		}
+		p.AddApp(config.App{Path: item.key()})
+	}
+	for i, item := range p.PersistentApps {
+		if item.GUID != old[i].GUID {
+			t.Fatalf("app #%d GUID = %d, want %d", i, item.GUID, old[i].GUID)
+		}
+	}
+}
+
+func Test_guid_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		seedA, seedB int64
+		same         bool
+	}{
+		"same seed":      {seedA: 1, seedB: 1, same: true},
+		"different seed": {seedA: 1, seedB: 2, same: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			a, b := &Plist{}, &Plist{}
+			a.SetSeed(tc.seedA)
+			b.SetSeed(tc.seedB)
+			for _, p := range []*Plist{a, b} {
+				p.AddApp(config.App{Path: "/Applications/Safari.app"})
+				p.AddApp(config.App{Path: "/Applications/Safari.app"})
+			}
+			if a.PersistentApps[0].GUID == a.PersistentApps[1].GUID {
+				t.Fatal("duplicate paths got the same GUID")
+			}
+			for i := range a.PersistentApps {
+				if got := a.PersistentApps[i].GUID == b.PersistentApps[i].GUID; got != tc.same {
+					t.Fatalf("app #%d same GUID = %v, want %v", i, got, tc.same)
+				}
+				if g := a.PersistentApps[i].GUID; g < 1 || g > maxGUID {
+					t.Fatalf("GUID %d out of range", g)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package dock
+
+import (
+	"fmt"
+
+	"github.com/5ouma/dorg/internal/config"
+)
+
+// corner returns the wvous-*-corner and wvous-*-modifier fields of a named corner.
+func (p *Plist) corner(name string) (action, modifier **int, err error) {
+	switch name {
+	case "top-left":
+		return &p.TopLeftCorner, &p.TopLeftModifier, nil
+	case "top-right":
+		return &p.TopRightCorner, &p.TopRightModifier, nil
+	case "bottom-left":
+		return &p.BottomLeftCorner, &p.BottomLeftModifier, nil
+	case "bottom-right":
+		return &p.BottomRightCorner, &p.BottomRightModifier, nil
+	}
+	return nil, nil, fmt.Errorf("unknown hot corner '%s'", name)
+}
+
+// ApplyHotCorners sets the given corners and leaves the others untouched.
+func (p *Plist) ApplyHotCorners(corners config.HotCorners) error {
+	for name, corner := range corners {
+		action, modifier, err := p.corner(name)
+		if err != nil {
+			return err
+		}
+		a, m := int(corner.Action), corner.Modifiers.Mask()
+		*action, *modifier = &a, &m
+	}
+	return nil
+}
+
+func (p *Plist) hotCorners() config.HotCorners {
+	var out config.HotCorners
+	for _, name := range config.Corners {
+		action, modifier, _ := p.corner(name)
+		if *action == nil {
+			continue
+		}
+		corner := config.HotCorner{Action: config.CornerAction(**action)}
+		if *modifier != nil {
+			corner.Modifiers = config.ModifiersFromMask(**modifier)
+		}
+		if out == nil {
+			out = config.HotCorners{}
+		}
+		out[name] = corner
+	}
+	return out
+}
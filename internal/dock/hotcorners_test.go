@@ -0,0 +1,49 @@
+package dock
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/5ouma/dorg/internal/config"
+)
+
+func Test_ApplyHotCorners(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		corners config.HotCorners
+		want    config.HotCorners
+		wantErr bool
+	}{
+		"set corners": {
+			corners: config.HotCorners{"top-right": {Action: config.ActionLockScreen, Modifiers: config.Modifiers{config.ModifierCommand}}},
+			want: config.HotCorners{
+				"top-left":  {Action: config.ActionMissionControl},
+				"top-right": {Action: config.ActionLockScreen, Modifiers: config.Modifiers{config.ModifierCommand}},
+			},
+		},
+		"replace corner": {
+			corners: config.HotCorners{"top-left": {Action: config.ActionNone}},
+			want:    config.HotCorners{"top-left": {Action: config.ActionNone}},
+		},
+		"nothing set":    {corners: nil, want: config.HotCorners{"top-left": {Action: config.ActionMissionControl}}},
+		"unknown corner": {corners: config.HotCorners{"middle": {Action: config.ActionDesktop}}, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Plist{TopLeftCorner: ptr(2), TopLeftModifier: ptr(0)}
+			err := p.ApplyHotCorners(tc.corners)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ApplyHotCorners error = %v, wantErr=%v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := p.hotCorners(); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("hot corners = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package dock
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const dockDomain = "com.apple.dock"
+
+// Location is the Dock plist a Plist is read from and written to, and the
+// home directory "~" expands to for its tiles.
+type Location struct {
+	Home  string
+	Plist string
+}
+
+// DefaultLocation is the current user's Dock plist.
+func DefaultLocation() (Location, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return Location{}, fmt.Errorf("failed to get user home directory: %v", err)
+	}
+	return Location{Home: home}, nil
+}
+
+func (l Location) PlistPath() string {
+	if l.Plist != "" {
+		return l.Plist
+	}
+	return filepath.Join(l.Home, dockPlistPath)
+}
+
+// domain is what defaults import writes to: the Dock domain for the current
+// user's plist, or the plist path itself for any other location.
+func (l Location) domain() string {
+	if def, err := DefaultLocation(); err == nil && def.PlistPath() == l.PlistPath() {
+		return dockDomain
+	}
+	return strings.TrimSuffix(l.PlistPath(), filepath.Ext(l.PlistPath()))
+}
+
+// Current reports whether l is the current user's Dock plist, the one the
+// running Dock reads.
+func (l Location) Current() bool {
+	return l.domain() == dockDomain
+}
+
+func Load(l Location) (*Plist, error) {
+	dPlist, err := LoadPlistFile(l.PlistPath())
+	if err != nil {
+		return nil, err
+	}
+	dPlist.SetLocation(l)
+	return dPlist, nil
+}
+
+// SetLocation changes where Save writes p and which home directory its tiles use.
+func (p *Plist) SetLocation(l Location) {
+	p.location = &l
+}
+
+func (p *Plist) getLocation() (Location, error) {
+	if p.location != nil {
+		return *p.location, nil
+	}
+	return DefaultLocation()
+}
+
+// WriteFile writes p straight to its plist file, bypassing defaults and the
+// Dock launch agent. Use it for Docks that are not currently running.
+func (p *Plist) WriteFile() error {
+	l, err := p.getLocation()
+	if err != nil {
+		return err
+	}
+
+	data, err := p.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.PlistPath()), 0755); err != nil {
+		return fmt.Errorf("failed to create dock plist dir: %w", err)
+	}
+	slog.Debug("writing dock plist", "plist", l.PlistPath())
+	if err := os.WriteFile(l.PlistPath(), data, 0600); err != nil {
+		return fmt.Errorf("failed to write dock plist: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package dock
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/5ouma/dorg/internal/config"
+)
+
+func Test_Location(t *testing.T) {
+	t.Parallel()
+
+	home, _ := os.UserHomeDir()
+	tests := map[string]struct {
+		loc        Location
+		wantPath   string
+		wantDomain string
+	}{
+		"current user": {loc: Location{Home: home}, wantPath: filepath.Join(home, dockPlistPath), wantDomain: "com.apple.dock"},
+		"other home":   {loc: Location{Home: "/Users/template"}, wantPath: "/Users/template/" + dockPlistPath, wantDomain: "/Users/template/Library/Preferences/com.apple.dock"},
+		"plist path":   {loc: Location{Home: home, Plist: "/tmp/dock.plist"}, wantPath: "/tmp/dock.plist", wantDomain: "/tmp/dock"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.loc.PlistPath(); got != tc.wantPath {
+				t.Fatalf("PlistPath() = %s, want %s", got, tc.wantPath)
+			}
+			if got := tc.loc.domain(); got != tc.wantDomain {
+				t.Fatalf("domain() = %s, want %s", got, tc.wantDomain)
+			}
+		})
+	}
+}
+
+func Test_WriteFile(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	p := &Plist{}
+	p.SetLocation(Location{Home: home})
+	p.AddApp(config.App{Path: "/Applications/Safari.app"})
+	if err := p.AddOther(config.Folder{Path: "~/Documents"}); err != nil {
+		t.Fatalf("AddOther error: %v", err)
+	}
+	if err := p.WriteFile(); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+
+	got, err := Load(Location{Home: home})
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if len(got.PersistentApps) != 1 || len(got.PersistentOthers) != 1 {
+		t.Fatalf("unexpected tiles: %+v", got)
+	}
+	if path := got.PersistentOthers[0].TileData.GetPath(); path != filepath.Join(home, "Documents") {
+		t.Fatalf("folder path = %s, want under %s", path, home)
+	}
+	conf, err := got.GenerateConfigFromPlist()
+	if err != nil {
+		t.Fatalf("GenerateConfigFromPlist error: %v", err)
+	}
+	if conf.Dock.Others[0].Path != "~/Documents" {
+		t.Fatalf("folder config path = %s, want ~/Documents", conf.Dock.Others[0].Path)
+	}
+}
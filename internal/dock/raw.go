@@ -0,0 +1,185 @@
+package dock
+
+import (
+	"fmt"
+	"reflect"
+
+	"howett.net/plist"
+)
+
+const (
+	persistentAppsKey   = "persistent-apps"
+	persistentOthersKey = "persistent-others"
+	recentAppsKey       = "recent-apps"
+)
+
+// raw keeps the dictionary a value was decoded from together with the view of
+// it dorg manages, so that only managed keys that actually changed are written back.
+type raw struct {
+	data    map[string]any
+	managed map[string]any
+}
+
+func newRaw(data map[string]any, v any) (raw, error) {
+	managed, err := structToMap(v)
+	if err != nil {
+		return raw{}, err
+	}
+	return raw{data: data, managed: managed}, nil
+}
+
+// apply overlays the current managed view of v onto the original dictionary.
+func (r raw) apply(v any) (map[string]any, error) {
+	managed, err := structToMap(v)
+	if err != nil {
+		return nil, err
+	}
+	return overlay(clone(r.data), managed, r.managed), nil
+}
+
+// ParsePlist decodes a Dock plist, keeping every key dorg does not manage so
+// that Marshal can write it back unchanged.
+func ParsePlist(data []byte) (*Plist, error) {
+	dPlist := new(Plist)
+	if _, err := plist.Unmarshal(data, dPlist); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dock plist: %v", err)
+	}
+
+	m := map[string]any{}
+	if _, err := plist.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dock plist: %v", err)
+	}
+
+	var err error
+	if dPlist.raw, err = newRaw(m, dPlist); err != nil {
+		return nil, err
+	}
+	for i, item := range rawItems(m, persistentAppsKey) {
+		if i < len(dPlist.PersistentApps) {
+			if dPlist.PersistentApps[i].raw, err = newRaw(item, dPlist.PersistentApps[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for i, item := range rawItems(m, recentAppsKey) {
+		if i < len(dPlist.RecentApps) {
+			if dPlist.RecentApps[i].raw, err = newRaw(item, dPlist.RecentApps[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for i, item := range rawItems(m, persistentOthersKey) {
+		if i < len(dPlist.PersistentOthers) {
+			if dPlist.PersistentOthers[i].raw, err = newRaw(item, dPlist.PersistentOthers[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	dPlist.indexGUIDs()
+
+	return dPlist, nil
+}
+
+// Marshal encodes the Dock plist in binary format. Keys dorg manages are taken
+// from p, every other key is carried over from the plist p was parsed from.
+func (p *Plist) Marshal() ([]byte, error) {
+	out, err := p.toMap()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := plist.Marshal(out, plist.BinaryFormat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode dock plist: %w", err)
+	}
+	return data, nil
+}
+
+func (p *Plist) toMap() (map[string]any, error) {
+	out, err := p.raw.apply(p)
+	if err != nil {
+		return nil, err
+	}
+
+	apps := make([]any, len(p.PersistentApps))
+	for i, item := range p.PersistentApps {
+		if apps[i], err = item.raw.apply(item); err != nil {
+			return nil, err
+		}
+	}
+	out[persistentAppsKey] = apps
+
+	others := make([]any, len(p.PersistentOthers))
+	for i, item := range p.PersistentOthers {
+		if others[i], err = item.raw.apply(item); err != nil {
+			return nil, err
+		}
+	}
+	out[persistentOthersKey] = others
+
+	// recent-apps is only written when the plist had it or it was set, so that
+	// plists without recents round-trip unchanged.
+	if _, ok := p.raw.data[recentAppsKey]; ok || p.RecentApps != nil {
+		recents := make([]any, len(p.RecentApps))
+		for i, item := range p.RecentApps {
+			if recents[i], err = item.raw.apply(item); err != nil {
+				return nil, err
+			}
+		}
+		out[recentAppsKey] = recents
+	}
+
+	return out, nil
+}
+
+func structToMap(v any) (map[string]any, error) {
+	data, err := plist.Marshal(v, plist.BinaryFormat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode dock plist: %w", err)
+	}
+	out := map[string]any{}
+	if _, err := plist.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to decode dock plist: %w", err)
+	}
+	return out, nil
+}
+
+func rawItems(m map[string]any, key string) []map[string]any {
+	list, _ := m[key].([]any)
+	out := make([]map[string]any, len(list))
+	for i, item := range list {
+		out[i], _ = item.(map[string]any)
+	}
+	return out
+}
+
+// overlay writes every value of managed that differs from base into dst,
+// descending into dictionaries present on both sides.
+func overlay(dst, managed, base map[string]any) map[string]any {
+	for k, v := range managed {
+		if old, ok := base[k]; ok && reflect.DeepEqual(old, v) {
+			continue
+		}
+		if vm, ok := v.(map[string]any); ok {
+			if dm, ok := dst[k].(map[string]any); ok {
+				bm, _ := base[k].(map[string]any)
+				dst[k] = overlay(dm, vm, bm)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+func clone(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		if vm, ok := v.(map[string]any); ok {
+			out[k] = clone(vm)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
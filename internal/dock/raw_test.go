@@ -0,0 +1,146 @@
+package dock
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/5ouma/dorg/internal/config"
+	"howett.net/plist"
+)
+
+func decode(t *testing.T, data []byte) map[string]any {
+	t.Helper()
+
+	out := map[string]any{}
+	if _, err := plist.Unmarshal(data, &out); err != nil {
+		t.Fatalf("failed to decode plist: %v", err)
+	}
+	return out
+}
+
+func Test_ParsePlist_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		fixture string
+	}{
+		"full dock":    {fixture: "dock.plist"},
+		"minimal dock": {fixture: "minimal.plist"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := os.ReadFile(filepath.Join("testdata", tc.fixture))
+			if err != nil {
+				t.Fatalf("failed to read fixture: %v", err)
+			}
+			p, err := ParsePlist(data)
+			if err != nil {
+				t.Fatalf("ParsePlist error: %v", err)
+			}
+			out, err := p.Marshal()
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			if got, want := decode(t, out), decode(t, data); !reflect.DeepEqual(got, want) {
+				t.Fatalf("round trip mismatch\n got: %#v\nwant: %#v", got, want)
+			}
+		})
+	}
+}
+
+func Test_Marshal_OnlyOverwritesManagedKeys(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "dock.plist"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	p, err := ParsePlist(data)
+	if err != nil {
+		t.Fatalf("ParsePlist error: %v", err)
+	}
+
+	p.AutoHide = false
+	p.PersistentOthers[0].TileData.Arrangement = 1
+	p.AddApp(config.App{Path: "file:///Applications/Safari.app/"})
+
+	out, err := p.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	got, want := decode(t, out), decode(t, data)
+
+	if got["autohide"] != false {
+		t.Fatalf("autohide = %v, want false", got["autohide"])
+	}
+	for _, key := range []string{"orientation", "mineffect", "mod-count", "recent-apps", "trash-full", "wvous-tl-corner", "wvous-tl-modifier", "wvous-br-corner", "tilesize", "largesize"} {
+		if !reflect.DeepEqual(got[key], want[key]) {
+			t.Fatalf("%s = %#v, want %#v", key, got[key], want[key])
+		}
+	}
+
+	apps := got[persistentAppsKey].([]any)
+	if len(apps) != 3 {
+		t.Fatalf("expected 3 apps, got %d", len(apps))
+	}
+	if !reflect.DeepEqual(apps[0], want[persistentAppsKey].([]any)[0]) {
+		t.Fatalf("untouched app tile changed: %#v", apps[0])
+	}
+
+	other := got[persistentOthersKey].([]any)[0].(map[string]any)["tile-data"].(map[string]any)
+	if other["arrangement"] != uint64(1) {
+		t.Fatalf("arrangement = %#v, want 1", other["arrangement"])
+	}
+	for _, key := range []string{"book", "preferreditemsize", "file-label"} {
+		wantOther := want[persistentOthersKey].([]any)[0].(map[string]any)["tile-data"].(map[string]any)
+		if !reflect.DeepEqual(other[key], wantOther[key]) {
+			t.Fatalf("tile-data %s = %#v, want %#v", key, other[key], wantOther[key])
+		}
+	}
+}
+
+func Test_Marshal_RecentApps(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		fixture string
+		edit    func(p *Plist)
+		want    []any
+		present bool
+	}{
+		"untouched without recents": {fixture: "minimal.plist", edit: func(p *Plist) {}, present: false},
+		"added":                     {fixture: "minimal.plist", edit: func(p *Plist) { p.AddRecent("file:///Applications/Safari.app/") }, present: true},
+		"cleared":                   {fixture: "dock.plist", edit: func(p *Plist) { p.ClearRecents() }, want: []any{}, present: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := os.ReadFile(filepath.Join("testdata", tc.fixture))
+			if err != nil {
+				t.Fatalf("failed to read fixture: %v", err)
+			}
+			p, err := ParsePlist(data)
+			if err != nil {
+				t.Fatalf("ParsePlist error: %v", err)
+			}
+			tc.edit(p)
+
+			out, err := p.Marshal()
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			got, ok := decode(t, out)[recentAppsKey]
+			if ok != tc.present {
+				t.Fatalf("recent-apps present = %v, want %v", ok, tc.present)
+			}
+			if tc.want != nil && !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("recent-apps = %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package dock
+
+import (
+	"slices"
+
+	"github.com/5ouma/dorg/internal/config"
+)
+
+// ReconcileApps turns the apps section into apps. Tiles already in the Dock
+// are kept as they are and moved into place; only the missing ones are added
+// and the ones no longer wanted removed.
+func (p *Plist) ReconcileApps(apps []config.App) {
+	want := make([]PAItem, len(apps))
+	for i, app := range apps {
+		want[i] = appItem(app)
+	}
+	p.PersistentApps = p.reconcileApps(persistentAppsKey, p.PersistentApps, want)
+}
+
+// ReconcileRecents turns the recent applications section into apps, the same
+// way as ReconcileApps.
+func (p *Plist) ReconcileRecents(apps []string) {
+	want := make([]PAItem, len(apps))
+	for i, app := range apps {
+		want[i] = appItem(config.App{Path: app})
+	}
+	p.RecentApps = p.reconcileApps(recentAppsKey, p.RecentApps, want)
+}
+
+// ReconcileOthers turns the others section into others, the same way as
+// ReconcileApps. Kept folders and links take their options from others.
+func (p *Plist) ReconcileOthers(others []config.Folder) error {
+	want := make([]POItem, len(others))
+	for i, other := range others {
+		item, err := p.otherItem(other)
+		if err != nil {
+			return err
+		}
+		want[i] = item
+	}
+
+	current := p.PersistentOthers
+	p.PersistentOthers = make([]POItem, len(want))
+	for i, j := range match(current, want) {
+		if j < 0 {
+			want[i].GUID = p.guid(persistentOthersKey, want[i].key())
+			p.PersistentOthers[i] = want[i]
+			continue
+		}
+		item := current[j]
+		p.claimGUID(persistentOthersKey, item.key(), item.GUID)
+		switch item.TileType {
+		case directoryTile:
+			item.TileData.Arrangement = want[i].TileData.Arrangement
+			item.TileData.DisplayAs = want[i].TileData.DisplayAs
+			item.TileData.ShowAs = want[i].TileData.ShowAs
+		case urlTile:
+			item.TileData.Label = want[i].TileData.Label
+		}
+		p.PersistentOthers[i] = item
+	}
+	return nil
+}
+
+func (p *Plist) reconcileApps(section string, current, want []PAItem) []PAItem {
+	out := make([]PAItem, len(want))
+	for i, j := range match(current, want) {
+		if j < 0 {
+			want[i].GUID = p.guid(section, want[i].key())
+			out[i] = want[i]
+			continue
+		}
+		out[i] = current[j]
+		p.claimGUID(section, current[j].key(), current[j].GUID)
+	}
+	return out
+}
+
+// match pairs every wanted tile with the first unused current tile of the same
+// key and returns its index, or -1 when the tile has to be added. Current tiles
+// left unpaired are the ones to remove.
+func match[T interface{ key() string }](current, want []T) []int {
+	free := map[string][]int{}
+	for i, item := range current {
+		free[item.key()] = append(free[item.key()], i)
+	}
+
+	out := make([]int, len(want))
+	for i, item := range want {
+		k := item.key()
+		if idx := free[k]; len(idx) > 0 {
+			out[i] = idx[0]
+			free[k] = idx[1:]
+			continue
+		}
+		out[i] = -1
+	}
+	return out
+}
+
+// claimGUID stops guid from being handed out again once its tile is kept.
+func (p *Plist) claimGUID(section, key string, guid int) {
+	k := guidKey(section, key)
+	ids := p.guids.existing[k]
+	if i := slices.Index(ids, guid); i >= 0 {
+		p.guids.existing[k] = slices.Delete(ids, i, i+1)
+	}
+}
@@ -0,0 +1,124 @@
+package dock
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/5ouma/dorg/internal/config"
+	"howett.net/plist"
+)
+
+func appTile(path string, guid int) map[string]any {
+	return map[string]any{
+		"GUID":      guid,
+		"tile-type": fileTile,
+		"tile-data": map[string]any{
+			"book":      []byte(path),
+			"file-data": map[string]any{"_CFURLString": "file://" + path + "/", "_CFURLStringType": 15},
+			"file-type": 41,
+		},
+	}
+}
+
+func Test_ReconcileApps(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		apps     []string
+		wantKept []int
+	}{
+		"unchanged": {apps: []string{"/A.app", "/B.app", "/C.app"}, wantKept: []int{1, 2, 3}},
+		"moved":     {apps: []string{"/C.app", "/A.app", "/B.app"}, wantKept: []int{3, 1, 2}},
+		"added":     {apps: []string{"/A.app", "/D.app", "/B.app", "/C.app"}, wantKept: []int{1, 0, 2, 3}},
+		"removed":   {apps: []string{"/C.app"}, wantKept: []int{3}},
+		"duplicate": {apps: []string{"/A.app", "/A.app"}, wantKept: []int{1, 0}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := plist.Marshal(map[string]any{
+				persistentAppsKey: []any{appTile("/A.app", 1), appTile("/B.app", 2), appTile("/C.app", 3)},
+			}, plist.BinaryFormat)
+			if err != nil {
+				t.Fatalf("failed to marshal plist: %v", err)
+			}
+			p, err := ParsePlist(data)
+			if err != nil {
+				t.Fatalf("ParsePlist error: %v", err)
+			}
+
+			before, err := p.Marshal()
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+
+			apps := make([]config.App, len(tc.apps))
+			for i, app := range tc.apps {
+				apps[i] = config.App{Path: app}
+			}
+			p.ReconcileApps(apps)
+
+			out, err := p.Marshal()
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			if got := !slices.Equal(out, before); got != (name != "unchanged") {
+				t.Fatalf("plist changed = %v", got)
+			}
+
+			var got map[string]any
+			if _, err := plist.Unmarshal(out, &got); err != nil {
+				t.Fatalf("failed to unmarshal plist: %v", err)
+			}
+			items := rawItems(got, persistentAppsKey)
+			if len(items) != len(tc.apps) {
+				t.Fatalf("got %d apps, want %d", len(items), len(tc.apps))
+			}
+			for i, item := range items {
+				_, hasBook := item["tile-data"].(map[string]any)["book"]
+				if kept := tc.wantKept[i] != 0; hasBook != kept {
+					t.Fatalf("app #%d kept metadata = %v, want %v", i, hasBook, kept)
+				}
+				if tc.wantKept[i] != 0 && item["GUID"] != uint64(tc.wantKept[i]) {
+					t.Fatalf("app #%d GUID = %v, want %d", i, item["GUID"], tc.wantKept[i])
+				}
+				if tc.wantKept[i] == 0 && slices.Contains([]any{uint64(1), uint64(2), uint64(3)}, item["GUID"]) {
+					t.Fatalf("new app #%d reused GUID %v", i, item["GUID"])
+				}
+			}
+		})
+	}
+}
+
+func Test_ReconcileOthers(t *testing.T) {
+	t.Parallel()
+
+	p := &Plist{location: &Location{Home: "/Users/dorg"}}
+	if err := p.AddOther(config.Folder{Path: "~/Downloads", Sort: config.SortName}); err != nil {
+		t.Fatalf("AddOther error: %v", err)
+	}
+	if err := p.AddOther(config.Folder{URL: "https://example.com", Label: "Example"}); err != nil {
+		t.Fatalf("AddOther error: %v", err)
+	}
+	p.indexGUIDs()
+	guids := []int{p.PersistentOthers[0].GUID, p.PersistentOthers[1].GUID}
+
+	err := p.ReconcileOthers([]config.Folder{
+		{URL: "https://example.com", Label: "Renamed"},
+		{Path: "~/Downloads", Sort: config.SortKind},
+	})
+	if err != nil {
+		t.Fatalf("ReconcileOthers error: %v", err)
+	}
+	url, dir := p.PersistentOthers[0], p.PersistentOthers[1]
+	if url.GUID != guids[1] || dir.GUID != guids[0] {
+		t.Fatalf("GUIDs = %d, %d, want %d, %d", url.GUID, dir.GUID, guids[1], guids[0])
+	}
+	if url.TileData.Label != "Renamed" {
+		t.Fatalf("label = %q, want Renamed", url.TileData.Label)
+	}
+	if dir.TileData.Arrangement != int(config.SortKind) {
+		t.Fatalf("arrangement = %d, want %d", dir.TileData.Arrangement, config.SortKind)
+	}
+}
@@ -0,0 +1,47 @@
+package dock
+
+import (
+	"net/url"
+	"strings"
+)
+
+const (
+	// urlStringTypePath marks a _CFURLString holding a plain POSIX path.
+	urlStringTypePath = 0
+	// urlStringTypeURL marks a _CFURLString holding an absolute URL.
+	urlStringTypeURL = 15
+)
+
+// fileURL encodes an absolute path as an RFC 8089 file URL. Directories, app
+// bundles included, end in a slash like the ones the Dock writes itself.
+func fileURL(path string, dir bool) string {
+	if dir && !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	u := url.URL{Scheme: "file", Path: path}
+	return u.String()
+}
+
+// newFileData points a tile at path, keeping file URLs given in the config as they are.
+func newFileData(path string, dir bool) FileData {
+	if !strings.HasPrefix(path, "file://") {
+		path = fileURL(path, dir)
+	}
+	return FileData{URLString: path, URLStringType: urlStringTypeURL}
+}
+
+// Path decodes the tile's location into a POSIX path without a trailing slash.
+func (d FileData) Path() string {
+	out := d.URLString
+	if d.URLStringType == urlStringTypeURL || strings.HasPrefix(out, "file://") {
+		if u, err := url.Parse(out); err == nil && u.Scheme == "file" {
+			out = u.Path
+		} else {
+			out = strings.ReplaceAll(strings.TrimPrefix(out, "file://"), "%20", " ")
+		}
+	}
+	if len(out) > 1 {
+		out = strings.TrimSuffix(out, "/")
+	}
+	return out
+}
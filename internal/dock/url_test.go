@@ -0,0 +1,82 @@
+package dock
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func Test_fileURL(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		path string
+		dir  bool
+		want string
+	}{
+		"app":          {path: "/Applications/Safari.app", dir: true, want: "file:///Applications/Safari.app/"},
+		"space":        {path: "/Users/me/My Folder", dir: true, want: "file:///Users/me/My%20Folder/"},
+		"percent":      {path: "/Users/me/100%", dir: true, want: "file:///Users/me/100%25/"},
+		"hash":         {path: "/Users/me/#1", dir: false, want: "file:///Users/me/%231"},
+		"question":     {path: "/Users/me/why?", dir: false, want: "file:///Users/me/why%3F"},
+		"japanese":     {path: "/Users/me/書類", dir: true, want: "file:///Users/me/%E6%9B%B8%E9%A1%9E/"},
+		"trailing dir": {path: "/Users/Shared/", dir: true, want: "file:///Users/Shared/"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := fileURL(tc.path, tc.dir); got != tc.want {
+				t.Fatalf("fileURL(%s) = %s, want %s", tc.path, got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_FileData_Path(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		data FileData
+		want string
+	}{
+		"file url":          {data: FileData{URLString: "file:///Applications/Safari.app/", URLStringType: 15}, want: "/Applications/Safari.app"},
+		"escaped":           {data: FileData{URLString: "file:///Users/me/100%25%20done/%E6%9B%B8%E9%A1%9E/", URLStringType: 15}, want: "/Users/me/100% done/書類"},
+		"hash":              {data: FileData{URLString: "file:///Users/me/%231", URLStringType: 15}, want: "/Users/me/#1"},
+		"localhost":         {data: FileData{URLString: "file://localhost/Applications/", URLStringType: 15}, want: "/Applications"},
+		"legacy path":       {data: FileData{URLString: "/Users/me/100%", URLStringType: 0}, want: "/Users/me/100%"},
+		"legacy file url":   {data: FileData{URLString: "file:///Users/test%20name/"}, want: "/Users/test name"},
+		"legacy bad escape": {data: FileData{URLString: "file:///Users/me/100%/"}, want: "/Users/me/100%"},
+		"root":              {data: FileData{URLString: "file:///", URLStringType: 15}, want: "/"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.data.Path(); got != tc.want {
+				t.Fatalf("Path() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func Fuzz_fileURL(f *testing.F) {
+	for _, seed := range []string{"/Applications/Safari.app", "/Users/me/My Folder", "/Users/me/100%", "/Users/me/#1?x", "/Users/me/書類", "/a//b", "/"} {
+		f.Add(seed, true)
+		f.Add(seed, false)
+	}
+	f.Fuzz(func(t *testing.T, path string, dir bool) {
+		if !strings.HasPrefix(path, "/") || !utf8.ValidString(path) || strings.ContainsRune(path, 0) {
+			t.Skip()
+		}
+
+		want := path
+		if len(want) > 1 {
+			want = strings.TrimSuffix(want, "/")
+		}
+		u := fileURL(path, dir)
+		if got := (FileData{URLString: u, URLStringType: urlStringTypeURL}).Path(); got != want {
+			t.Fatalf("Path(fileURL(%q)) = %q via %q, want %q", path, got, u, want)
+		}
+	})
+}
@@ -0,0 +1,227 @@
+package remote
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Stdin names standard input as a config file.
+	Stdin = "-"
+	// DefaultTimeout bounds a whole HTTP request.
+	DefaultTimeout = 30 * time.Second
+)
+
+// IsURL reports whether name is an HTTP(S) URL rather than a local path.
+func IsURL(name string) bool {
+	return strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://")
+}
+
+// IsLocal reports whether name is a local file.
+func IsLocal(name string) bool {
+	return name != Stdin && !IsURL(name)
+}
+
+// DefaultCacheDir returns $DORG_CACHE_DIR, or the dorg/remote directory of the
+// XDG cache directory.
+func DefaultCacheDir() (string, error) {
+	if dir := os.Getenv("DORG_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "dorg", "remote"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %v", err)
+	}
+	return filepath.Join(home, ".cache", "dorg", "remote"), nil
+}
+
+// Fetcher reads config files from local paths, HTTP(S) URLs and standard
+// input. Each file is read once; later reads return the same data.
+type Fetcher struct {
+	Client *http.Client
+	// CacheDir keeps downloaded files for conditional requests. Caching is
+	// disabled when empty.
+	CacheDir string
+	// Checksums pins files to a SHA-256 digest, as "sha256:<hex>" or "<hex>".
+	Checksums map[string]string
+	Stdin     io.Reader
+
+	mu   sync.Mutex
+	read map[string][]byte
+}
+
+func NewFetcher(timeout time.Duration) *Fetcher {
+	f := &Fetcher{
+		Client: &http.Client{Timeout: timeout},
+		Stdin:  os.Stdin,
+	}
+	if dir, err := DefaultCacheDir(); err == nil {
+		f.CacheDir = dir
+	}
+	return f
+}
+
+// Read returns the contents of name.
+func (f *Fetcher) Read(name string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if data, ok := f.read[name]; ok {
+		return data, nil
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	switch {
+	case name == Stdin:
+		data, err = io.ReadAll(f.Stdin)
+	case IsURL(name):
+		data, err = f.fetch(name)
+	default:
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(name, data, f.Checksums[name]); err != nil {
+		return nil, err
+	}
+
+	if f.read == nil {
+		f.read = map[string][]byte{}
+	}
+	f.read[name] = data
+	return data, nil
+}
+
+func verify(name string, data []byte, checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+	want := strings.ToLower(strings.TrimPrefix(checksum, "sha256:"))
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("checksum mismatch for %s: got sha256:%s, want sha256:%s", name, got, want)
+	}
+	return nil
+}
+
+// entry is what the cache remembers about a URL next to its body.
+type entry struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+func (f *Fetcher) fetch(url string) ([]byte, error) {
+	client := f.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	cached, body := f.cached(url)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if body != nil {
+			slog.Warn("using cached config", "url", url, "error", err)
+			return body, nil
+		}
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			slog.Warn("failed to close response body", "url", url, "error", err)
+		}
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && body != nil:
+		slog.Debug("config not modified", "url", url)
+		return body, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	f.store(url, entry{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}, data)
+	return data, nil
+}
+
+func (f *Fetcher) cachePath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(f.CacheDir, hex.EncodeToString(sum[:]))
+}
+
+// cached returns the cache entry and body of url, or a nil body when there is none.
+func (f *Fetcher) cached(url string) (entry, []byte) {
+	if f.CacheDir == "" {
+		return entry{}, nil
+	}
+	path := f.cachePath(url)
+	meta, err := os.ReadFile(path + ".json")
+	if err != nil {
+		return entry{}, nil
+	}
+	var e entry
+	if err := json.Unmarshal(meta, &e); err != nil {
+		slog.Debug("ignoring broken cache entry", "url", url, "error", err)
+		return entry{}, nil
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return entry{}, nil
+	}
+	return e, body
+}
+
+func (f *Fetcher) store(url string, e entry, body []byte) {
+	if f.CacheDir == "" {
+		return
+	}
+	if err := os.MkdirAll(f.CacheDir, 0750); err != nil {
+		slog.Warn("failed to create cache dir", "dir", f.CacheDir, "error", err)
+		return
+	}
+	meta, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	path := f.cachePath(url)
+	if err := os.WriteFile(path, body, 0600); err != nil {
+		slog.Warn("failed to cache config", "url", url, "error", err)
+		return
+	}
+	if err := os.WriteFile(path+".json", meta, 0600); err != nil {
+		slog.Warn("failed to cache config", "url", url, "error", err)
+	}
+}
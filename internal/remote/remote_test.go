@@ -0,0 +1,187 @@
+package remote
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const body = "dock_items: {apps: [/A.app]}\n"
+
+func Test_Fetcher_Read(t *testing.T) {
+	t.Parallel()
+
+	sum := sha256.Sum256([]byte(body))
+	digest := hex.EncodeToString(sum[:])
+
+	local := filepath.Join(t.TempDir(), "dorg.yml")
+	if err := os.WriteFile(local, []byte(body), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	tests := map[string]struct {
+		name     string
+		checksum string
+		wantErr  bool
+	}{
+		"stdin":             {name: Stdin},
+		"local file":        {name: local},
+		"missing file":      {name: filepath.Join(t.TempDir(), "missing.yml"), wantErr: true},
+		"checksum":          {name: local, checksum: "sha256:" + digest},
+		"bare checksum":     {name: Stdin, checksum: strings.ToUpper(digest)},
+		"checksum mismatch": {name: local, checksum: "sha256:" + strings.Repeat("0", 64), wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			f := &Fetcher{Stdin: strings.NewReader(body)}
+			if tc.checksum != "" {
+				f.Checksums = map[string]string{tc.name: tc.checksum}
+			}
+			// Reading twice also covers standard input, which can only be read once.
+			for range 2 {
+				got, err := f.Read(tc.name)
+				if (err != nil) != tc.wantErr {
+					t.Fatalf("Read error = %v, wantErr=%v", err, tc.wantErr)
+				}
+				if err == nil && string(got) != body {
+					t.Fatalf("Read = %q, want %q", got, body)
+				}
+			}
+		})
+	}
+}
+
+func Test_Fetcher_fetch(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		header      string
+		value       string
+		conditional string
+	}{
+		"etag":          {header: "ETag", value: `"v1"`, conditional: "If-None-Match"},
+		"last modified": {header: "Last-Modified", value: "Mon, 02 Jan 2006 15:04:05 GMT", conditional: "If-Modified-Since"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests, notModified atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				if r.Header.Get(tc.conditional) == tc.value {
+					notModified.Add(1)
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set(tc.header, tc.value)
+				_, _ = w.Write([]byte(body))
+			}))
+			defer srv.Close()
+
+			cache := t.TempDir()
+			for i := range 2 {
+				// A new fetcher each time, like separate runs of dorg.
+				f := &Fetcher{Client: srv.Client(), CacheDir: cache}
+				got, err := f.Read(srv.URL + "/dorg.yml")
+				if err != nil {
+					t.Fatalf("run %d: Read error: %v", i, err)
+				}
+				if string(got) != body {
+					t.Fatalf("run %d: Read = %q, want %q", i, got, body)
+				}
+			}
+			if requests.Load() != 2 || notModified.Load() != 1 {
+				t.Fatalf("requests = %d, not modified = %d, want 2 and 1", requests.Load(), notModified.Load())
+			}
+		})
+	}
+}
+
+func Test_Fetcher_fetchErrors(t *testing.T) {
+	t.Parallel()
+
+	var down atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/slow":
+			time.Sleep(200 * time.Millisecond)
+		case down.Load():
+			http.Error(w, "broken", http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	cache := t.TempDir()
+	url := srv.URL + "/dorg.yml"
+	f := &Fetcher{Client: srv.Client(), CacheDir: cache}
+	if _, err := f.Read(url); err != nil {
+		t.Fatalf("Read error: %v", err)
+	}
+
+	down.Store(true)
+	f = &Fetcher{Client: srv.Client(), CacheDir: cache}
+	if _, err := f.Read(url); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Fatalf("Read error = %v, want the server error", err)
+	}
+
+	client := srv.Client()
+	client.Timeout = 50 * time.Millisecond
+	f = &Fetcher{Client: client}
+	if _, err := f.Read(srv.URL + "/slow"); err == nil {
+		t.Fatalf("Read did not time out")
+	}
+
+	// An unreachable server falls back to the cached copy.
+	srv.Close()
+	f = &Fetcher{Client: &http.Client{Timeout: time.Second}, CacheDir: cache}
+	got, err := f.Read(url)
+	if err != nil {
+		t.Fatalf("Read error with the server down: %v", err)
+	}
+	if string(got) != body {
+		t.Fatalf("Read = %q, want the cached %q", got, body)
+	}
+	if _, err := f.Read(srv.URL + "/other.yml"); err == nil {
+		t.Fatalf("Read of an uncached URL succeeded with the server down")
+	}
+}
+
+func Test_IsURL(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		name      string
+		wantURL   bool
+		wantLocal bool
+	}{
+		"https":    {name: "https://example.com/dorg.yml", wantURL: true},
+		"http":     {name: "http://example.com/dorg.yml", wantURL: true},
+		"stdin":    {name: Stdin},
+		"relative": {name: "dorg.yml", wantLocal: true},
+		"absolute": {name: "/etc/dorg.yml", wantLocal: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsURL(tc.name); got != tc.wantURL {
+				t.Fatalf("IsURL(%s) = %v, want %v", tc.name, got, tc.wantURL)
+			}
+			if got := IsLocal(tc.name); got != tc.wantLocal {
+				t.Fatalf("IsLocal(%s) = %v, want %v", tc.name, got, tc.wantLocal)
+			}
+		})
+	}
+}
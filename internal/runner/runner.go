@@ -0,0 +1,108 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Runner executes external commands such as defaults, launchctl and killall.
+type Runner interface {
+	Run(ctx context.Context, cmd string, args ...string) (string, error)
+}
+
+// Exec runs commands as real processes.
+type Exec struct{}
+
+func (Exec) Run(ctx context.Context, cmd string, args ...string) (string, error) {
+	c := new(exec.Cmd)
+
+	if ctx != nil {
+		c = exec.CommandContext(ctx, cmd, args...)
+	} else {
+		c = exec.Command(cmd, args...)
+	}
+
+	output, err := c.Output()
+	if err != nil {
+		return string(output), err
+	}
+
+	if ctx != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("command %s timed out", cmd)
+		}
+	}
+
+	return string(output), nil
+}
+
+type Call struct {
+	Cmd  string
+	Args []string
+}
+
+func (c Call) String() string {
+	return strings.Join(append([]string{c.Cmd}, c.Args...), " ")
+}
+
+// Recorder is a fake Runner that records every call instead of running it.
+type Recorder struct {
+	// Respond, if set, decides the output and error of each call.
+	Respond func(ctx context.Context, c Call) (string, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *Recorder) Run(ctx context.Context, cmd string, args ...string) (string, error) {
+	c := Call{Cmd: cmd, Args: args}
+
+	r.mu.Lock()
+	r.calls = append(r.calls, c)
+	r.mu.Unlock()
+
+	if r.Respond == nil {
+		return "", nil
+	}
+	return r.Respond(ctx, c)
+}
+
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Call(nil), r.calls...)
+}
+
+// Commands returns each recorded call as a single command line.
+func (r *Recorder) Commands() []string {
+	var out []string
+	for _, c := range r.Calls() {
+		out = append(out, c.String())
+	}
+	return out
+}
+
+// FailOn makes a Recorder return err for every call whose command line starts with prefix.
+func FailOn(prefix string, err error) func(context.Context, Call) (string, error) {
+	return func(_ context.Context, c Call) (string, error) {
+		if strings.HasPrefix(c.String(), prefix) {
+			return "", err
+		}
+		return "", nil
+	}
+}
+
+// TimeOut makes a Recorder fail every call whose command line starts with
+// prefix the way Exec does when the command's context deadline is exceeded.
+func TimeOut(prefix string) func(context.Context, Call) (string, error) {
+	return func(_ context.Context, c Call) (string, error) {
+		if strings.HasPrefix(c.String(), prefix) {
+			return "", fmt.Errorf("command %s timed out", c.Cmd)
+		}
+		return "", nil
+	}
+}
@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Exec(t *testing.T) {
+	tests := map[string]struct {
+		ctx     context.Context
+		cmd     string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		"echo succeeds": {ctx: context.Background(), cmd: "echo", args: []string{"hello"}, want: "hello\n", wantErr: false},
+		"command times out": {ctx: func() context.Context {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			_ = cancel
+			return ctx
+		}(), cmd: "sleep", args: []string{"1"}, wantErr: true},
+		"nonexistent command": {ctx: context.Background(), cmd: "no-such-cmd-xyz", args: nil, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Exec{}.Run(tc.ctx, tc.cmd, tc.args...)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Run(%s) error = %v, wantErr=%v", tc.cmd, err, tc.wantErr)
+			}
+			if err == nil && got != tc.want {
+				t.Fatalf("Run(%s) = %q, want %q", tc.cmd, got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_Recorder(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+	tests := map[string]struct {
+		respond  func(context.Context, Call) (string, error)
+		wantErrs []bool
+	}{
+		"no responder": {respond: nil, wantErrs: []bool{false, false}},
+		"fail on":      {respond: FailOn("/bin/launchctl unload", errBoom), wantErrs: []bool{true, false}},
+		"time out":     {respond: TimeOut("killall"), wantErrs: []bool{false, true}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &Recorder{Respond: tc.respond}
+			_, err1 := r.Run(context.Background(), "/bin/launchctl", "unload", "/x.plist")
+			_, err2 := r.Run(context.Background(), "killall", "Dock")
+			if (err1 != nil) != tc.wantErrs[0] || (err2 != nil) != tc.wantErrs[1] {
+				t.Fatalf("errors = %v, %v, want %v", err1, err2, tc.wantErrs)
+			}
+
+			want := []string{"/bin/launchctl unload /x.plist", "killall Dock"}
+			got := r.Commands()
+			if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+				t.Fatalf("Commands() = %v, want %v", got, want)
+			}
+		})
+	}
+}
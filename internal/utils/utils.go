@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"os/exec"
 	"runtime/debug"
 	"strings"
 	"time"
 
 	"charm.land/lipgloss/v2"
 	"charm.land/lipgloss/v2/compat"
+	"github.com/5ouma/dorg/internal/runner"
 	"github.com/pkg/errors"
 )
 
@@ -31,29 +31,22 @@ var (
 	CheckedItem = item.
 			Foreground(compat.CompleteColor{TrueColor: lipgloss.Color("#63b946"), ANSI256: lipgloss.Color("41")}).
 			SetString("✔︎")
+	AddedItem = item.
+			Foreground(compat.CompleteColor{TrueColor: lipgloss.Color("#63b946"), ANSI256: lipgloss.Color("41")}).
+			SetString("+")
+	RemovedItem = item.
+			Foreground(compat.CompleteColor{TrueColor: lipgloss.Color("#ff3b30"), ANSI256: lipgloss.Color("196")}).
+			SetString("-")
+	MovedItem = item.
+			Foreground(compat.CompleteColor{TrueColor: lipgloss.Color("#007aff"), ANSI256: lipgloss.Color("27")}).
+			SetString("↕")
+	ModifiedItem = item.
+			Foreground(compat.CompleteColor{TrueColor: lipgloss.Color("#ff9500"), ANSI256: lipgloss.Color("208")}).
+			SetString("~")
 )
 
 func RunCommand(ctx context.Context, cmd string, args ...string) (string, error) {
-	c := new(exec.Cmd)
-
-	if ctx != nil {
-		c = exec.CommandContext(ctx, cmd, args...)
-	} else {
-		c = exec.Command(cmd, args...)
-	}
-
-	output, err := c.Output()
-	if err != nil {
-		return string(output), err
-	}
-
-	if ctx != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			return "", fmt.Errorf("command %s timed out", cmd)
-		}
-	}
-
-	return string(output), nil
+	return runner.Exec{}.Run(ctx, cmd, args...)
 }
 
 func SetLogLevel(verbose bool) int {
@@ -63,12 +56,15 @@ func SetLogLevel(verbose bool) int {
 	return int(slog.LevelWarn)
 }
 
-func RestartDock() error {
+// DockRestartDelay is how long RestartDock waits for the Dock to come back.
+var DockRestartDelay = 2 * time.Second
+
+func RestartDock(r runner.Runner) error {
 	slog.Debug("restarting Dock")
-	if _, err := RunCommand(context.Background(), "killall", "Dock"); err != nil {
+	if _, err := r.Run(context.Background(), "killall", "Dock"); err != nil {
 		return errors.Wrap(err, "killing Dock process failed")
 	}
-	time.Sleep(2 * time.Second)
+	time.Sleep(DockRestartDelay)
 	return nil
 }
 